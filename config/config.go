@@ -8,17 +8,25 @@ import (
 )
 
 type Config struct {
-	DBUrl      string `envconfig:"DB_URL"`
-	DBUser     string `envconfig:"DB_USER"`
-	DBPassword string `envconfig:"DB_PASSWORD"`
-	FilePath   string `envconfig:"FILE_PATH"`
-	TableName  string `envconfig:"TABLE_NAME"`
-	CtlFilePath string `envconfig:"CTL_FILE_PATH"`
+	DBUrl            string `envconfig:"DB_URL"`
+	DBUser           string `envconfig:"DB_USER"`
+	DBPassword       string `envconfig:"DB_PASSWORD"`
+	FilePath         string `envconfig:"FILE_PATH"`
+	TableName        string `envconfig:"TABLE_NAME"`
+	CtlFilePath      string `envconfig:"CTL_FILE_PATH"`
+	LengthSemantics  string `envconfig:"LENGTH_SEMANTICS"`
+	MaxStringSize    int    `envconfig:"MAX_STRING_SIZE"`
+	OracleVersion    string `envconfig:"ORACLE_VERSION"`
+	NamingStrategy   string `envconfig:"NAMING_STRATEGY"`
+	HeaderDictionary string `envconfig:"HEADER_DICTIONARY"`
+	HeaderRules      string `envconfig:"HEADER_RULES"`
+	TableOwner       string `envconfig:"TABLE_OWNER"`
+	Tablespace       string `envconfig:"TABLESPACE"`
 }
 
 func LoadConfig() (*Config, error) {
 	err := godotenv.Load()
-	
+
 	if err != nil {
 		log.Printf("Error loading .env file: %v", err)
 	} else {
@@ -64,8 +64,7 @@ func ConvertFileToUtf8(inputPath, outputPath string) error {
 	}
 	defer outputFile.Close()
 
-	reader := transform.NewReader(inputFile, charmap.Windows1251.NewDecoder())
-	_, err = io.Copy(outputFile, reader)
+	_, err = io.Copy(outputFile, NewUtf8Reader(inputFile))
 	if err != nil {
 		return fmt.Errorf("error converting file to UTF-8: %v", err)
 	}
@@ -73,53 +72,54 @@ func ConvertFileToUtf8(inputPath, outputPath string) error {
 	return nil
 }
 
+// NewUtf8Reader decodes a Windows-1251 stream to UTF-8 as it is read.
+func NewUtf8Reader(r io.Reader) io.Reader {
+	return transform.NewReader(r, charmap.Windows1251.NewDecoder())
+}
+
 func FilterConvertedFile(filePath string, tableConfig *db.TableConfig, delimiter rune) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("error opening converted file: %v", err)
 	}
+	// Closed before the rename below, where this second Close does nothing
 	defer file.Close()
 
 	reader := csv.NewReader(file)
 	reader.Comma = delimiter
-	records, err := reader.ReadAll()
+	headers, err := reader.Read()
 	if err != nil {
 		return fmt.Errorf("error reading converted file: %v", err)
 	}
 
-	headers := records[0]
+	// Columns are matched by position, as several headers may be the same
+	if len(headers) != len(tableConfig.ColumnsOrder) {
+		return fmt.Errorf("file has %d columns but the table config has %d", len(headers), len(tableConfig.ColumnsOrder))
+	}
 	var filteredHeaders []string
-	var filteredRecords [][]string
-
-	// Identify columns to keep based on tableConfig
 	keepColumns := make(map[int]bool)
 	for i, header := range headers {
-		for _, colInfo := range tableConfig.Columns {
-			if colInfo.OriginalName == header && colInfo.Create {
-				keepColumns[i] = true
-				filteredHeaders = append(filteredHeaders, header)
-				break
-			}
+		if tableConfig.Columns[tableConfig.ColumnsOrder[i]].Create {
+			keepColumns[i] = true
+			filteredHeaders = append(filteredHeaders, header)
 		}
 	}
 
-	// Filter records based on keepColumns
-	for _, record := range records[1:] {
-		var filteredRecord []string
-		for i, value := range record {
-			if keepColumns[i] {
-				filteredRecord = append(filteredRecord, value)
-			}
-		}
-		filteredRecords = append(filteredRecords, filteredRecord)
-	}
-
-	// Write the filtered records to a new converted file
-	filteredFile, err := os.Create(filePath)
+	// Stream the filtered records to a temporary file that replaces the
+	// converted file once complete, so large files never sit in memory
+	filteredFilePath := filePath + ".tmp"
+	filteredFile, err := os.Create(filteredFilePath)
 	if err != nil {
 		return fmt.Errorf("error creating filtered file: %v", err)
 	}
-	defer filteredFile.Close()
+	// Remove the temporary file unless it replaced the converted one
+	renamed := false
+	defer func() {
+		if !renamed {
+			filteredFile.Close()
+			os.Remove(filteredFilePath)
+		}
+	}()
 
 	writer := csv.NewWriter(filteredFile)
 	writer.Comma = delimiter
@@ -129,17 +129,43 @@ func FilterConvertedFile(filePath string, tableConfig *db.TableConfig, delimiter
 		return fmt.Errorf("error writing headers to filtered file: %v", err)
 	}
 
-	// Write the filtered records
-	for _, record := range filteredRecords {
-		if err := writer.Write(record); err != nil {
+	// Filter records based on keepColumns
+	var filteredRecord []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading converted file: %v", err)
+		}
+
+		filteredRecord = filteredRecord[:0]
+		for i, value := range record {
+			if keepColumns[i] {
+				filteredRecord = append(filteredRecord, value)
+			}
+		}
+		if err := writer.Write(filteredRecord); err != nil {
 			return fmt.Errorf("error writing records to filtered file: %v", err)
 		}
 	}
 
 	writer.Flush()
-	return writer.Error()
-}
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("error writing records to filtered file: %v", err)
+	}
 
+	file.Close()
+	if err := filteredFile.Close(); err != nil {
+		return fmt.Errorf("error closing filtered file: %v", err)
+	}
+	if err := os.Rename(filteredFilePath, filePath); err != nil {
+		return fmt.Errorf("error replacing converted file: %v", err)
+	}
+	renamed = true
+	return nil
+}
 
 func detectFileEncoding(file *os.File) (string, error) {
 	buffer := make([]byte, 1024)
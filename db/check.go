@@ -0,0 +1,117 @@
+package db
+
+import "fmt"
+
+// Mismatch is a column that would make SQL*Loader reject rows.
+type Mismatch struct {
+	Column  string
+	Problem string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("  %s: %s", m.Column, m.Problem)
+}
+
+// CheckTable compares the columns loaded by the .ctl file with those of the
+// existing table: each must exist with a type that holds the values in the
+// file, even if it is not the type in the config. NOT NULL columns the file
+// does not fill are reported too.
+func CheckTable(tableConfig *TableConfig, existing []TableColumn) []Mismatch {
+	byName := make(map[string]TableColumn, len(existing))
+	for _, column := range existing {
+		byName[column.Name] = column
+	}
+
+	var mismatches []Mismatch
+	seen := make(map[string]bool)
+	for _, colName := range tableConfig.ColumnsOrder {
+		colInfo := tableConfig.Columns[colName]
+		if !colInfo.Create {
+			continue
+		}
+		name := tableConfig.storedName(colName)
+		seen[name] = true
+		target := tableConfig.TargetName(colName)
+
+		column, ok := byName[name]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{Column: target, Problem: "missing from the table"})
+			continue
+		}
+		columnType, _ := colInfo.ColumnType(tableConfig.Metadata)
+		if problem := loadProblem(colInfo, columnType, column); problem != "" {
+			mismatches = append(mismatches, Mismatch{Column: target, Problem: problem})
+		}
+	}
+
+	for _, column := range existing {
+		if !seen[column.Name] && !column.Nullable {
+			mismatches = append(mismatches, Mismatch{Column: column.Name, Problem: "NOT NULL but not in the config"})
+		}
+	}
+	return mismatches
+}
+
+// CheckTableFromConfig reads the target table and checks it against the
+// table config before loading.
+func CheckTableFromConfig(user, password, dsn string, tableConfig *TableConfig) ([]Mismatch, error) {
+	db, err := openDB(user, password, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	columns, err := ReadTableColumns(db, tableConfig.Metadata.Owner, tableConfig.Metadata.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", tableConfig.Metadata.QualifiedTableName())
+	}
+	return CheckTable(tableConfig, columns), nil
+}
+
+// loadProblem describes why the values of a configured column cannot be
+// loaded into an existing one, or returns "" when they can.
+func loadProblem(colInfo ColumnInfo, columnType ColumnType, column TableColumn) string {
+	switch columnType.Name {
+	case "VARCHAR2", "NVARCHAR2", "CHAR", "NCHAR":
+		// Fixed and variable width text take the same values
+		switch column.Type {
+		case "CLOB", "NCLOB":
+			return ""
+		case "VARCHAR2", "NVARCHAR2", "CHAR", "NCHAR":
+			if column.Semantics == "BYTE" && column.Length < colInfo.byteLength() {
+				return fmt.Sprintf("%s is too narrow for values of %d bytes", column.TypeSQL(), colInfo.byteLength())
+			}
+			if length := valueLength(columnType.Name, colInfo.Length); column.Length < length {
+				return fmt.Sprintf("%s is too narrow for values of %d characters", column.TypeSQL(), colInfo.Length)
+			}
+			return ""
+		}
+	case "NUMBER", "NUMERIC", "INTEGER":
+		if column.Type != "NUMBER" && column.Type != "FLOAT" {
+			break
+		}
+		// Only the digits of the values in the file have to fit; extra
+		// fractional digits are rounded
+		intDigits := colInfo.Precision - colInfo.Scale
+		if column.Type == "NUMBER" && column.Precision > 0 && colInfo.Precision > 0 && intDigits > column.Precision-column.Scale {
+			return fmt.Sprintf("%s is too small for values with %d integer digits", column.TypeSQL(), intDigits)
+		}
+		return ""
+	}
+
+	planned := columnType.SQL()
+	if columnType.Length > 0 {
+		columnType.Length = valueLength(columnType.Name, colInfo.Length)
+	}
+	widened, compatible := compareColumn(columnType, column, colInfo.byteLength())
+	switch {
+	case !compatible:
+		return fmt.Sprintf("%s in the table, %s in the config", column.TypeSQL(), planned)
+	case widened != nil:
+		return fmt.Sprintf("%s is too narrow for values of %d characters", column.TypeSQL(), colInfo.Length)
+	}
+	return ""
+}
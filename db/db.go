@@ -2,35 +2,331 @@ package db
 
 import (
 	"database/sql"
-	"encoding/csv"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
 
 	_ "github.com/godror/godror"
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/inference"
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
 )
 
 type ColumnInfo struct {
 	OriginalName string `json:"original_name"`
-	Type   string `json:"type"`
-	Length int    `json:"length"`
-	Create bool   `json:"create"`
+	// TargetName is the name of the column in Oracle. Edit it to rename the
+	// column; the key in TableConfig.Columns stays its identity.
+	TargetName string `json:"target_name,omitempty"`
+	// RenamedFrom is the name derived from the header when it was not a
+	// valid Oracle identifier, and RenameReason says why.
+	RenamedFrom  string `json:"renamed_from,omitempty"`
+	RenameReason string `json:"rename_reason,omitempty"`
+	// DuplicateOf is the column whose name the header also mapped to.
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	Type        string `json:"type"`
+	Kind        string `json:"kind,omitempty"`
+	Length      int    `json:"length"`
+	// Bytes is the length of the longest value in UTF-8, which is what a
+	// column with BYTE length semantics needs in an AL32UTF8 database.
+	Bytes     int    `json:"bytes,omitempty"`
+	Precision int    `json:"precision,omitempty"`
+	Scale     int    `json:"scale,omitempty"`
+	Format    string `json:"format,omitempty"`
+	// TypeReason explains an inferred type that reviewers might not expect.
+	TypeReason string `json:"type_reason,omitempty"`
+	// NumericLocale is set for NUMBER columns that need converting while
+	// loading.
+	NumericLocale *NumericLocale `json:"numeric_locale,omitempty"`
+	// Approximate marks a type and length inferred from a sample of the rows.
+	Approximate bool `json:"approximate,omitempty"`
+	// Nullable is false for columns that were never empty, which are created
+	// NOT NULL. Columns without the setting are nullable.
+	Nullable  *bool `json:"nullable,omitempty"`
+	NullCount int   `json:"null_count"`
+	// Domain lists the values seen in boolean and enumeration columns.
+	// CheckDomain restricts the column to them with a CHECK constraint.
+	Domain      []string     `json:"domain,omitempty"`
+	CheckDomain bool         `json:"check_domain,omitempty"`
+	Stats       *ColumnStats `json:"stats,omitempty"`
+	// Rules lists the header rules that changed the column, in order.
+	Rules  []string `json:"rules,omitempty"`
+	Create bool     `json:"create"`
+}
+
+// ColumnStats profiles the values of a column to help decide whether to
+// create it. Empty values are counted in ColumnInfo.NullCount.
+type ColumnStats struct {
+	Min              string   `json:"min,omitempty"`
+	Max              string   `json:"max,omitempty"`
+	DistinctEstimate uint64   `json:"distinct_estimate"`
+	Samples          []string `json:"samples,omitempty"`
+}
+
+// IsNullable reports whether the column may hold NULL values.
+func (c ColumnInfo) IsNullable() bool {
+	return c.Nullable == nil || *c.Nullable
+}
+
+// isLOB reports whether the column is a CLOB or NCLOB.
+func (c ColumnInfo) isLOB() bool {
+	columnType, _ := ParseType(c.Type)
+	return columnType.Name == "CLOB" || columnType.Name == "NCLOB"
+}
+
+// NumericLocale records how the numbers of a column are written in the
+// source file.
+type NumericLocale struct {
+	DecimalSeparator string `json:"decimal_separator"`
+	GroupSeparators  string `json:"group_separators,omitempty"`
 }
 
 type TableConfig struct {
 	Columns      map[string]ColumnInfo `json:"columns"`
 	Metadata     Metadata              `json:"metadata"`
 	ColumnsOrder []string              `json:"columns_order"`
+	// KeyCandidates lists columns, or column combinations, that were unique
+	// and never empty in the file. Copy one into Keys to create it.
+	KeyCandidates [][]string      `json:"key_candidates,omitempty"`
+	Keys          []KeyConstraint `json:"keys,omitempty"`
+}
+
+// TargetName returns the name a column is created with in Oracle, which
+// defaults to its key in Columns.
+func (t *TableConfig) TargetName(colName string) string {
+	if target := t.Columns[colName].TargetName; target != "" {
+		return target
+	}
+	return colName
+}
+
+// ColumnSQL returns the target name of a column as written in SQL, quoted
+// when the names were passed through from the headers.
+func (t *TableConfig) ColumnSQL(colName string) string {
+	if t.Metadata.NamingStrategy == util.NamingPassthrough {
+		return `"` + t.TargetName(colName) + `"`
+	}
+	return t.TargetName(colName)
+}
+
+// Validate checks that the columns, their order, their target names and the
+// keys agree with each other, which hand edits can break.
+func (t *TableConfig) Validate() error {
+	var problems []string
+
+	listed := make(map[string]bool, len(t.ColumnsOrder))
+	for _, colName := range t.ColumnsOrder {
+		if listed[colName] {
+			problems = append(problems, fmt.Sprintf("column %s is listed twice in columns_order", colName))
+		}
+		listed[colName] = true
+		if _, ok := t.Columns[colName]; !ok {
+			problems = append(problems, fmt.Sprintf("column %s in columns_order is not in columns", colName))
+		}
+	}
+
+	maxLength := t.Metadata.identifierLength()
+	quoted := t.Metadata.NamingStrategy == util.NamingPassthrough
+	targets := make(map[string]string, len(t.Columns))
+	for colName, colInfo := range t.Columns {
+		if !listed[colName] {
+			problems = append(problems, fmt.Sprintf("column %s is missing from columns_order", colName))
+		}
+		if !colInfo.Create {
+			continue
+		}
+		if _, err := colInfo.ColumnType(t.Metadata); err != nil {
+			problems = append(problems, fmt.Sprintf("column %s: %v", colName, err))
+		}
+		target := t.TargetName(colName)
+		switch {
+		case len(target) > maxLength:
+			problems = append(problems, fmt.Sprintf("target name %s of column %s is longer than %d bytes", target, colName, maxLength))
+		case quoted && strings.Contains(target, `"`):
+			problems = append(problems, fmt.Sprintf("target name %s of column %s contains double quotes", target, colName))
+		case !quoted && !util.IsUnquotedIdentifier(target):
+			problems = append(problems, fmt.Sprintf("target name %s of column %s is not a valid Oracle identifier", target, colName))
+		}
+		// Unquoted identifiers are not case sensitive
+		if !quoted {
+			target = strings.ToUpper(target)
+		}
+		if other, ok := targets[target]; ok {
+			first, second := min(other, colName), max(other, colName)
+			problems = append(problems, fmt.Sprintf("columns %s and %s have the same target name", first, second))
+		}
+		targets[target] = colName
+	}
+
+	primaryKeys := 0
+	for i, key := range t.Keys {
+		switch strings.ToUpper(key.Type) {
+		case "PRIMARY KEY":
+			primaryKeys++
+		case "UNIQUE":
+		default:
+			problems = append(problems, fmt.Sprintf("key %d has type %q, expected PRIMARY KEY or UNIQUE", i+1, key.Type))
+		}
+		if len(key.Columns) == 0 {
+			problems = append(problems, fmt.Sprintf("key %d has no columns", i+1))
+		}
+		if key.Name != "" && (len(key.Name) > maxLength || !util.IsUnquotedIdentifier(key.Name)) {
+			problems = append(problems, fmt.Sprintf("key %d has name %s, which is not a valid Oracle identifier of at most %d bytes", i+1, key.Name, maxLength))
+		}
+		for _, colName := range key.Columns {
+			colInfo, ok := t.Columns[colName]
+			switch {
+			case !ok || !colInfo.Create:
+				problems = append(problems, fmt.Sprintf("key %d uses column %s, which is not created", i+1, colName))
+			case colInfo.isLOB():
+				problems = append(problems, fmt.Sprintf("key %d uses column %s, which is a LOB", i+1, colName))
+			}
+		}
+	}
+	if primaryKeys > 1 {
+		problems = append(problems, "more than one primary key")
+	}
+	if _, err := ParseLoadMode(t.Metadata.LoadMode); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if owner := t.Metadata.Owner; owner != "" && (len(owner) > maxLength || !util.IsUnquotedIdentifier(owner)) {
+		problems = append(problems, fmt.Sprintf("owner %s is not a valid Oracle identifier", owner))
+	}
+	if tablespace := t.Metadata.Tablespace; tablespace != "" && (len(tablespace) > maxLength || !util.IsUnquotedIdentifier(tablespace)) {
+		problems = append(problems, fmt.Sprintf("tablespace %s is not a valid Oracle identifier", tablespace))
+	}
+	if pctFree := t.Metadata.PctFree; pctFree != nil && (*pctFree < 0 || *pctFree > 99) {
+		problems = append(problems, fmt.Sprintf("pctfree %d is not between 0 and 99", *pctFree))
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid table config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// KeyConstraint is a PRIMARY KEY or UNIQUE constraint created with the table.
+// Unnamed constraints are named after the table.
+type KeyConstraint struct {
+	Name    string   `json:"name,omitempty"`
+	Type    string   `json:"type"`
+	Columns []string `json:"columns"`
 }
 
 type Metadata struct {
-	RowCount  int    `json:"rowCount"`
-	TableName string `json:"tableName"`
+	RowCount  int       `json:"rowCount"`
+	TableName string    `json:"tableName"`
+	Sampling  *Sampling `json:"sampling,omitempty"`
+	// LengthSemantics is BYTE or CHAR, written into VARCHAR2 columns. The
+	// session default applies when it is empty.
+	LengthSemantics string `json:"length_semantics,omitempty"`
+	// MaxStringSize is the VARCHAR2 limit of the database, 4000 or 32767
+	// with MAX_STRING_SIZE=EXTENDED.
+	MaxStringSize int `json:"max_string_size,omitempty"`
+	// MaxIdentifierLength is the identifier limit in bytes, 30 or 128 since
+	// Oracle 12.2.
+	MaxIdentifierLength int `json:"max_identifier_length,omitempty"`
+	// NamingStrategy records how column names were made from the headers.
+	// With passthrough they are quoted identifiers.
+	NamingStrategy string `json:"naming_strategy,omitempty"`
+	// HeaderDictionary is the dictionary file the column names were
+	// looked up in.
+	HeaderDictionary string `json:"header_dictionary,omitempty"`
+	// HeaderRules is the rule file applied after inference.
+	HeaderRules string `json:"header_rules,omitempty"`
+	// LoadMode is one of the Load modes, INSERT when empty.
+	LoadMode string `json:"load_mode,omitempty"`
+	// Owner is the schema of the table, the current schema when empty.
+	Owner string `json:"owner,omitempty"`
+	// Tablespace, PctFree, Compress and NoLogging are the storage
+	// attributes of the created table. The database defaults apply when
+	// they are not set.
+	Tablespace string `json:"tablespace,omitempty"`
+	PctFree    *int   `json:"pctfree,omitempty"`
+	Compress   bool   `json:"compress,omitempty"`
+	NoLogging  bool   `json:"nologging,omitempty"`
+}
+
+// identifierLength returns the identifier limit of the database in bytes.
+func (m Metadata) identifierLength() int {
+	if m.MaxIdentifierLength == 0 {
+		return util.ShortIdentifierLength
+	}
+	return m.MaxIdentifierLength
 }
 
-func GenerateTableConfig(filePath string, tableName string, delimiter rune) (TableConfig, error) {
+// QualifiedTableName returns the table name prefixed with its owner, if any.
+func (m Metadata) QualifiedTableName() string {
+	if m.Owner == "" {
+		return m.TableName
+	}
+	return m.Owner + "." + m.TableName
+}
+
+// Sampling records how the rows that column types were inferred from were
+// chosen. With the head strategy the file is not read to the end, so
+// RowCount only counts the rows read.
+type Sampling struct {
+	Strategy    string `json:"strategy"`
+	Size        int    `json:"size"`
+	RowsSampled int    `json:"rows_sampled"`
+}
+
+// VARCHAR2 limits for MAX_STRING_SIZE=STANDARD and EXTENDED.
+const (
+	StandardMaxStringSize = 4000
+	ExtendedMaxStringSize = 32767
+)
+
+// GenerateOptions tunes how GenerateTableConfig scans the file.
+type GenerateOptions struct {
+	inference.Options
+	// LengthSemantics is BYTE, CHAR or empty for the session default.
+	LengthSemantics string
+	// MaxStringSize is the longest VARCHAR2 column; longer text columns
+	// become CLOB. Zero means StandardMaxStringSize.
+	MaxStringSize int
+	// MaxIdentifierLength limits column names in bytes. Zero means
+	// util.ShortIdentifierLength.
+	MaxIdentifierLength int
+	// NamingStrategy is one of the util.Naming strategies, unidecode when
+	// empty.
+	NamingStrategy string
+	// HeaderDictionary is the path of a dictionary file naming known
+	// headers, consulted before the naming strategy.
+	HeaderDictionary string
+	// HeaderRules is the path of a rule file overriding inference.
+	HeaderRules string
+	// Owner and Tablespace are copied into the metadata.
+	Owner      string
+	Tablespace string
+}
+
+func GenerateTableConfig(filePath string, tableName string, delimiter rune, opts GenerateOptions) (TableConfig, error) {
+	switch opts.LengthSemantics {
+	case "", "BYTE", "CHAR":
+	default:
+		return TableConfig{}, fmt.Errorf("invalid length semantics %q, expected BYTE or CHAR", opts.LengthSemantics)
+	}
+	switch opts.MaxStringSize {
+	case 0:
+		opts.MaxStringSize = StandardMaxStringSize
+	case StandardMaxStringSize, ExtendedMaxStringSize:
+	default:
+		return TableConfig{}, fmt.Errorf("invalid max string size %d, expected %d or %d", opts.MaxStringSize, StandardMaxStringSize, ExtendedMaxStringSize)
+	}
+	if opts.NamingStrategy == "" {
+		opts.NamingStrategy = util.NamingUnidecode
+	}
+	switch opts.MaxIdentifierLength {
+	case 0:
+		opts.MaxIdentifierLength = util.ShortIdentifierLength
+	case util.ShortIdentifierLength, util.LongIdentifierLength:
+	default:
+		return TableConfig{}, fmt.Errorf("invalid max identifier length %d, expected %d or %d", opts.MaxIdentifierLength, util.ShortIdentifierLength, util.LongIdentifierLength)
+	}
+
 	file, err := os.Open(filePath)
 
 	if err != nil {
@@ -39,81 +335,184 @@ func GenerateTableConfig(filePath string, tableName string, delimiter rune) (Tab
 
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = delimiter
-	records, err := reader.ReadAll()
+	table, err := inference.Scan(file, delimiter, opts.Options)
 
 	if err != nil {
 		return TableConfig{}, err
 	}
 
-	originalHeaders := records[0]
-	headers := util.TransliterateHeaders(originalHeaders)
-	for i, header := range headers {
-		headers[i] = util.ToLowerSnakeCase(header)
+	originalHeaders := table.Headers
+	headers, err := util.ColumnNames(originalHeaders, opts.NamingStrategy)
+	if err != nil {
+		return TableConfig{}, err
 	}
-
-	columnInfo := make(map[string]*ColumnInfo, len(headers))
-
-	for i, header := range headers {
-		columnInfo[header] = &ColumnInfo{
-			Type: "VARCHAR2", 
-			Length: 1, // Set minimum length for oracle columns is 1
-			Create: originalHeaders[i] != "",
-			OriginalName: originalHeaders[i],
+	var rules Rules
+	if opts.HeaderRules != "" {
+		if rules, err = LoadRules(opts.HeaderRules); err != nil {
+			return TableConfig{}, err
 		}
 	}
-
-	for _, row := range records[1:] {
-		for i, value := range row {
-			header := headers[i]
-			col := columnInfo[header]
-			if len(value) > col.Length {
-				col.Length = len(value)
-			}
-			if col.Type != "VARCHAR2" { // If already set to VARCHAR2, no need to check further
-				if util.IsNumeric(value) {
-					if len(value) > 11 {
-						col.Type = "VARCHAR2" // Handle long numbers as VARCHAR2
-					} else {
-						col.Type = "NUMBER"
-					}
-				} else {
-					col.Type = "VARCHAR2"
+	entries := make([]*DictionaryEntry, len(headers))
+	if opts.HeaderDictionary != "" {
+		dictionary, err := LoadDictionary(opts.HeaderDictionary)
+		if err != nil {
+			return TableConfig{}, err
+		}
+		for i, header := range originalHeaders {
+			if entry, ok := dictionary.Lookup(header); ok {
+				entries[i] = &entry
+				if entry.Name != "" {
+					headers[i] = entry.Name
 				}
 			}
 		}
 	}
+	renamedFrom := make([]string, len(headers))
+	renameReasons := make([]string, len(headers))
+	for i, header := range headers {
+		if opts.NamingStrategy == util.NamingPassthrough {
+			headers[i], renameReasons[i] = util.SafeQuotedIdentifier(header, opts.MaxIdentifierLength)
+		} else {
+			headers[i], renameReasons[i] = util.SafeIdentifier(header, opts.MaxIdentifierLength)
+		}
+		if renameReasons[i] != "" {
+			renamedFrom[i] = header
+		}
+	}
+	headers, duplicateOf := util.UniqueIdentifiers(headers, opts.MaxIdentifierLength)
+	duplicates := make([]string, len(headers))
+	for i, j := range duplicateOf {
+		if j < 0 {
+			continue
+		}
+		duplicates[i] = headers[j]
+		if renamedFrom[i] == "" {
+			renamedFrom[i] = headers[j]
+		}
+		renameReasons[i] = strings.TrimPrefix(renameReasons[i]+", duplicate name", ", ")
+	}
 
 	result := make(map[string]ColumnInfo, len(headers))
-	for header, col := range columnInfo {
-		result[header] = *col
+	for i, header := range headers {
+		inferred := table.Columns[i].Result()
+		var locale *NumericLocale
+		if inferred.Locale != nil {
+			locale = &NumericLocale{
+				DecimalSeparator: string(inferred.Locale.Decimal),
+				GroupSeparators:  inferred.Locale.Groups,
+			}
+		}
+		if inferred.Type == "VARCHAR2" && inferred.Length > opts.MaxStringSize {
+			inferred.Type = "CLOB"
+			inferred.Reason = fmt.Sprintf("longest value has %d characters, over the VARCHAR2 limit of %d", inferred.Length, opts.MaxStringSize)
+		}
+		// A sample or an empty file cannot show that a column is never empty
+		nullable := inferred.Nulls > 0 || inferred.Values == 0 || opts.Sample != ""
+		// Only boolean domains are complete enough to enforce by default;
+		// text domains are what this file happens to hold, and a sample
+		// may miss values of either
+		checkDomain := inferred.Kind == inference.KindBoolean && len(inferred.Domain) > 0 && opts.Sample == ""
+		result[header] = ColumnInfo{
+			OriginalName:  originalHeaders[i],
+			TargetName:    header,
+			RenamedFrom:   renamedFrom[i],
+			RenameReason:  renameReasons[i],
+			DuplicateOf:   duplicates[i],
+			Type:          inferred.Type,
+			Kind:          inferred.Kind,
+			Length:        inferred.Length,
+			Bytes:         inferred.Bytes,
+			Precision:     inferred.Precision,
+			Scale:         inferred.Scale,
+			Format:        inferred.Format,
+			TypeReason:    inferred.Reason,
+			NumericLocale: locale,
+			Approximate:   opts.Sample != "",
+			Nullable:      &nullable,
+			NullCount:     inferred.Nulls,
+			Domain:        inferred.Domain,
+			CheckDomain:   checkDomain,
+			Stats: &ColumnStats{
+				Min:              inferred.Min,
+				Max:              inferred.Max,
+				DistinctEstimate: inferred.Distinct,
+				Samples:          inferred.Samples,
+			},
+			Create: originalHeaders[i] != "",
+		}
+		colInfo := result[header]
+		if entry := entries[i]; entry != nil {
+			entry.apply(&colInfo, entry.describe())
+		}
+		rules.Apply(header, &colInfo)
+		result[header] = colInfo
 	}
 
 	tableConfig := TableConfig{
-		Columns:      result,
-		Metadata:     Metadata{RowCount: len(records) - 1, TableName: tableName},
+		Columns: result,
+		Metadata: Metadata{
+			RowCount:            table.Rows,
+			TableName:           tableName,
+			LengthSemantics:     opts.LengthSemantics,
+			MaxStringSize:       opts.MaxStringSize,
+			MaxIdentifierLength: opts.MaxIdentifierLength,
+			NamingStrategy:      opts.NamingStrategy,
+			HeaderDictionary:    opts.HeaderDictionary,
+			HeaderRules:         opts.HeaderRules,
+			LoadMode:            LoadInsert,
+			Owner:               opts.Owner,
+			Tablespace:          opts.Tablespace,
+		},
 		ColumnsOrder: headers,
 	}
 
+	for _, key := range table.Keys {
+		var columns []string
+		for _, i := range key {
+			// LOB columns cannot be part of a key
+			if colInfo := result[headers[i]]; colInfo.Create && !colInfo.isLOB() {
+				columns = append(columns, headers[i])
+			}
+		}
+		if len(columns) == len(key) {
+			tableConfig.KeyCandidates = append(tableConfig.KeyCandidates, columns)
+		}
+	}
+
+	if opts.Sample != "" {
+		tableConfig.Metadata.Sampling = &Sampling{
+			Strategy:    opts.Sample,
+			Size:        opts.SampleSize,
+			RowsSampled: table.Sampled,
+		}
+	}
+
 	return tableConfig, nil
 }
 
-func CreateTableFromConfig(user, password, dsn string, tableConfig *TableConfig) error {
+func openDB(user, password, dsn string) (*sql.DB, error) {
 	connString := fmt.Sprintf("%s/%s@%s", user, password, dsn)
 	db, err := sql.Open("godror", connString)
 	if err != nil {
-		return fmt.Errorf("error connecting to the database: %v", err)
+		return nil, fmt.Errorf("error connecting to the database: %v", err)
+	}
+	return db, nil
+}
+
+func CreateTableFromConfig(user, password, dsn string, tableConfig *TableConfig) error {
+	db, err := openDB(user, password, dsn)
+	if err != nil {
+		return err
 	}
 	defer db.Close()
 
-	tableExists, err := checkIfTableExists(db, tableConfig.Metadata.TableName)
+	tableExists, err := checkIfTableExists(db, tableConfig.Metadata.Owner, tableConfig.Metadata.TableName)
 	if err != nil {
 		return fmt.Errorf("error checking if table exists: %v", err)
 	}
 
 	if tableExists {
-		log.Printf("Table %s already exists", tableConfig.Metadata.TableName)
+		log.Printf("Table %s already exists", tableConfig.Metadata.QualifiedTableName())
 		return nil
 	}
 
@@ -125,18 +524,21 @@ func CreateTableFromConfig(user, password, dsn string, tableConfig *TableConfig)
 		return fmt.Errorf("error executing script for table: %v", err)
 	}
 
-	log.Printf("Table %s created successfully", tableConfig.Metadata.TableName)
+	log.Printf("Table %s created successfully", tableConfig.Metadata.QualifiedTableName())
 	return nil
 }
 
-func checkIfTableExists(db *sql.DB, tableName string) (bool, error) {
+// checkIfTableExists looks for the table in the schema of owner, or in the
+// current schema when owner is empty.
+func checkIfTableExists(db *sql.DB, owner, tableName string) (bool, error) {
 	query := `
 	SELECT COUNT(*) 
 	FROM all_tables 
-	WHERE table_name = :1
+	WHERE owner = NVL(:1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA'))
+	AND table_name = :2
 	`
 	var count int
-	err := db.QueryRow(query, strings.ToUpper(tableName)).Scan(&count)
+	err := db.QueryRow(query, strings.ToUpper(owner), strings.ToUpper(tableName)).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -144,11 +546,10 @@ func checkIfTableExists(db *sql.DB, tableName string) (bool, error) {
 	return count > 0, nil
 }
 
-
 // GenerateCreateTableSQL generates a SQL CREATE TABLE statement from the given TableConfig.
 func GenerateCreateTableSQL(tableConfig *TableConfig) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", tableConfig.Metadata.TableName))
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", tableConfig.Metadata.QualifiedTableName()))
 	first := true
 	for _, colName := range tableConfig.ColumnsOrder {
 		colInfo := tableConfig.Columns[colName]
@@ -159,12 +560,81 @@ func GenerateCreateTableSQL(tableConfig *TableConfig) string {
 			sb.WriteString(",\n")
 		}
 		first = false
-		if colInfo.Type == "NUMBER" || colInfo.Type == "NUMERIC" || colInfo.Type == "DATE" || colInfo.Type == "TIMESTAMP WITH TIME ZONE" {
-			sb.WriteString(fmt.Sprintf("  %s %s", colName, colInfo.Type))
-		} else {
-			sb.WriteString(fmt.Sprintf("  %s VARCHAR2(%d)", colName, colInfo.Length))
+		sb.WriteString(fmt.Sprintf("  %s %s", tableConfig.ColumnSQL(colName), columnTypeSQL(colInfo, tableConfig.Metadata)))
+		if !colInfo.IsNullable() {
+			sb.WriteString(" NOT NULL")
+		}
+		if colInfo.CheckDomain && len(colInfo.Domain) > 0 {
+			sb.WriteString(" " + checkConstraintSQL(tableConfig.ColumnSQL(colName), colInfo))
 		}
 	}
+	uniqueCount := 0
+	for _, key := range tableConfig.Keys {
+		keyType := strings.ToUpper(key.Type)
+		name := key.Name
+		if name == "" && keyType == "PRIMARY KEY" {
+			name = keyName("pk_", tableConfig.Metadata, "")
+		} else if name == "" {
+			uniqueCount++
+			name = keyName("uk_", tableConfig.Metadata, fmt.Sprintf("_%d", uniqueCount))
+		}
+		columns := make([]string, len(key.Columns))
+		for i, colName := range key.Columns {
+			columns[i] = tableConfig.ColumnSQL(colName)
+		}
+		sb.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s %s (%s)", name, keyType, strings.Join(columns, ", ")))
+	}
 	sb.WriteString("\n)")
+	sb.WriteString(storageSQL(tableConfig.Metadata))
+	return sb.String()
+}
+
+// storageSQL returns the storage attributes of the table, one per line.
+func storageSQL(metadata Metadata) string {
+	var sb strings.Builder
+	if metadata.PctFree != nil {
+		sb.WriteString(fmt.Sprintf("\nPCTFREE %d", *metadata.PctFree))
+	}
+	if metadata.Compress {
+		sb.WriteString("\nCOMPRESS")
+	}
+	if metadata.NoLogging {
+		sb.WriteString("\nNOLOGGING")
+	}
+	if metadata.Tablespace != "" {
+		sb.WriteString("\nTABLESPACE " + metadata.Tablespace)
+	}
 	return sb.String()
-}
\ No newline at end of file
+}
+
+// keyName names an unnamed constraint after the table, shortening the table
+// name so that the name fits the identifier limit.
+func keyName(prefix string, metadata Metadata, suffix string) string {
+	tableName := metadata.TableName
+	if over := len(prefix) + len(tableName) + len(suffix) - metadata.identifierLength(); over > 0 {
+		tableName = strings.TrimRight(tableName[:max(len(tableName)-over, 0)], "_")
+	}
+	return prefix + tableName + suffix
+}
+
+// columnTypeSQL returns the Oracle data type of a column as written in DDL.
+func columnTypeSQL(colInfo ColumnInfo, metadata Metadata) string {
+	columnType, err := colInfo.ColumnType(metadata)
+	if err != nil {
+		return colInfo.Type
+	}
+	return columnType.SQL()
+}
+
+// checkConstraintSQL restricts a column to the values of its domain.
+func checkConstraintSQL(colName string, colInfo ColumnInfo) string {
+	values := make([]string, len(colInfo.Domain))
+	for i, value := range colInfo.Domain {
+		if columnType, _ := ParseType(colInfo.Type); columnType.IsNumeric() {
+			values[i] = value
+		} else {
+			values[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+	}
+	return fmt.Sprintf("CHECK (%s IN (%s))", colName, strings.Join(values, ", "))
+}
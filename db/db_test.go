@@ -0,0 +1,256 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestGenerateCreateTableSQL(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"id":     {Type: "NUMBER", Precision: 10, Nullable: boolPtr(false), Create: true},
+			"amount": {Type: "NUMBER", Precision: 12, Scale: 2, Nullable: boolPtr(true), Create: true},
+			"name":   {Type: "VARCHAR2", Length: 50, Create: true},
+			"notes":  {Type: "CLOB", Length: 5000, Create: true},
+			"active": {Type: "CHAR", Length: 1, Domain: []string{"N", "Y"}, CheckDomain: true, Create: true},
+			"paid":   {Type: "NUMBER", Precision: 1, Domain: []string{"0", "1"}, CheckDomain: true, Create: true},
+			"status": {Type: "VARCHAR2", Length: 6, Domain: []string{"new", "closed"}, Create: true},
+			"empty1": {Type: "VARCHAR2", Length: 1, Create: false},
+		},
+		Metadata:     Metadata{TableName: "payments", LengthSemantics: "CHAR"},
+		ColumnsOrder: []string{"id", "amount", "name", "notes", "active", "paid", "status", "empty1"},
+		Keys: []KeyConstraint{
+			{Type: "PRIMARY KEY", Columns: []string{"id"}},
+			{Type: "unique", Columns: []string{"name", "status"}},
+			{Name: "uk_payments_status", Type: "UNIQUE", Columns: []string{"status"}},
+		},
+	}
+
+	expected := `CREATE TABLE payments (
+  id NUMBER(10) NOT NULL,
+  amount NUMBER(12,2),
+  name VARCHAR2(50 CHAR),
+  notes CLOB,
+  active CHAR(1 CHAR) CHECK (active IN ('N', 'Y')),
+  paid NUMBER(1) CHECK (paid IN (0, 1)),
+  status VARCHAR2(6 CHAR),
+  CONSTRAINT pk_payments PRIMARY KEY (id),
+  CONSTRAINT uk_payments_1 UNIQUE (name, status),
+  CONSTRAINT uk_payments_status UNIQUE (status)
+)`
+
+	result := GenerateCreateTableSQL(tableConfig)
+	if result != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, result)
+	}
+}
+
+func TestGenerateCreateTableSQL_Passthrough(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"Номер": {Type: "NUMBER", Precision: 10, Nullable: boolPtr(false), Create: true},
+			"Стан":  {Type: "CHAR", Length: 1, Domain: []string{"А", "Н"}, CheckDomain: true, Create: true},
+		},
+		Metadata:     Metadata{TableName: "payments", NamingStrategy: util.NamingPassthrough},
+		ColumnsOrder: []string{"Номер", "Стан"},
+		Keys:         []KeyConstraint{{Type: "PRIMARY KEY", Columns: []string{"Номер"}}},
+	}
+
+	expected := `CREATE TABLE payments (
+  "Номер" NUMBER(10) NOT NULL,
+  "Стан" CHAR(1) CHECK ("Стан" IN ('А', 'Н')),
+  CONSTRAINT pk_payments PRIMARY KEY ("Номер")
+)`
+
+	result := GenerateCreateTableSQL(tableConfig)
+	if result != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, result)
+	}
+}
+
+func TestGenerateCreateTableSQL_TargetName(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"kod_iedrpou": {TargetName: "edrpou", Type: "VARCHAR2", Length: 8, Create: true},
+			"suma":        {TargetName: "amount", Type: "NUMBER", Precision: 12, Scale: 2, Create: true},
+		},
+		Metadata:     Metadata{TableName: "payments"},
+		ColumnsOrder: []string{"kod_iedrpou", "suma"},
+		Keys:         []KeyConstraint{{Type: "PRIMARY KEY", Columns: []string{"kod_iedrpou"}}},
+	}
+
+	expected := `CREATE TABLE payments (
+  edrpou VARCHAR2(8),
+  amount NUMBER(12,2),
+  CONSTRAINT pk_payments PRIMARY KEY (edrpou)
+)`
+
+	result := GenerateCreateTableSQL(tableConfig)
+	if result != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, result)
+	}
+}
+
+func TestGenerateCreateTableSQL_Types(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"code":    {Type: "CHAR", Length: 3, Create: true},
+			"name":    {Type: "NVARCHAR2", Length: 80, Create: true},
+			"note":    {Type: "VARCHAR2(200 BYTE)", Length: 120, Create: true},
+			"count":   {Type: "INTEGER", Length: 5, Precision: 5, Create: true},
+			"rate":    {Type: "FLOAT(63)", Length: 12, Create: true},
+			"hash":    {Type: "RAW", Length: 32, Create: true},
+			"created": {Type: "TIMESTAMP(6)", Length: 26, Create: true},
+			"body":    {Type: "NCLOB", Length: 9000, Create: true},
+		},
+		Metadata:     Metadata{TableName: "payments", LengthSemantics: "CHAR"},
+		ColumnsOrder: []string{"code", "name", "note", "count", "rate", "hash", "created", "body"},
+	}
+
+	expected := `CREATE TABLE payments (
+  code CHAR(3 CHAR),
+  name NVARCHAR2(80),
+  note VARCHAR2(200 BYTE),
+  count INTEGER,
+  rate FLOAT(63),
+  hash RAW(16),
+  created TIMESTAMP(6),
+  body NCLOB
+)`
+
+	result := GenerateCreateTableSQL(tableConfig)
+	if result != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, result)
+	}
+}
+
+func TestGenerateCreateTableSQL_Storage(t *testing.T) {
+	pctFree := 0
+	tableConfig := &TableConfig{
+		Columns:      map[string]ColumnInfo{"id": {Type: "NUMBER", Precision: 10, Create: true}},
+		Metadata:     Metadata{TableName: "payments", Owner: "SALES", Tablespace: "USERS", PctFree: &pctFree, Compress: true, NoLogging: true},
+		ColumnsOrder: []string{"id"},
+		Keys:         []KeyConstraint{{Type: "PRIMARY KEY", Columns: []string{"id"}}},
+	}
+
+	expected := `CREATE TABLE SALES.payments (
+  id NUMBER(10),
+  CONSTRAINT pk_payments PRIMARY KEY (id)
+)
+PCTFREE 0
+COMPRESS
+NOLOGGING
+TABLESPACE USERS`
+
+	result := GenerateCreateTableSQL(tableConfig)
+	if result != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, result)
+	}
+}
+
+func TestGenerateCreateTableSQL_LongKeyNames(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"id":     {Type: "NUMBER", Precision: 10, Create: true},
+			"doc_no": {Type: "VARCHAR2", Length: 20, Create: true},
+		},
+		Metadata:     Metadata{TableName: "monthly_bank_statement_payments"},
+		ColumnsOrder: []string{"id", "doc_no"},
+		Keys: []KeyConstraint{
+			{Type: "PRIMARY KEY", Columns: []string{"id"}},
+			{Type: "UNIQUE", Columns: []string{"doc_no"}},
+		},
+	}
+
+	expected := `CREATE TABLE monthly_bank_statement_payments (
+  id NUMBER(10),
+  doc_no VARCHAR2(20),
+  CONSTRAINT pk_monthly_bank_statement_paym PRIMARY KEY (id),
+  CONSTRAINT uk_monthly_bank_statement_pa_1 UNIQUE (doc_no)
+)`
+
+	result := GenerateCreateTableSQL(tableConfig)
+	if result != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, result)
+	}
+}
+
+func TestGenerateTableConfig_CheckDomain(t *testing.T) {
+	csvFile := writeFile(t, t.TempDir(), "data.csv", "active;currency\nY;UAH\nY;UAH\nY;UAH\n")
+
+	tableConfig, err := GenerateTableConfig(csvFile, "payments", ';', GenerateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to generate table config: %v", err)
+	}
+
+	active := tableConfig.Columns["active"]
+	if !active.CheckDomain || !reflect.DeepEqual(active.Domain, []string{"N", "Y"}) {
+		t.Errorf("Expected a checked [N Y] domain but got %v checked %t", active.Domain, active.CheckDomain)
+	}
+	currency := tableConfig.Columns["currency"]
+	if currency.CheckDomain || !reflect.DeepEqual(currency.Domain, []string{"UAH"}) {
+		t.Errorf("Expected an unchecked [UAH] domain but got %v checked %t", currency.Domain, currency.CheckDomain)
+	}
+}
+
+func TestTableConfig_Validate(t *testing.T) {
+	valid := func() *TableConfig {
+		return &TableConfig{
+			Columns: map[string]ColumnInfo{
+				"id":     {TargetName: "id", Type: "NUMBER", Precision: 10, Create: true},
+				"name":   {TargetName: "client_name", Type: "VARCHAR2", Length: 40, Create: true},
+				"empty1": {Create: false},
+			},
+			ColumnsOrder: []string{"id", "name", "empty1"},
+			Keys:         []KeyConstraint{{Type: "PRIMARY KEY", Columns: []string{"id"}}},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Expected a valid config but got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*TableConfig)
+	}{
+		{"missing from order", func(c *TableConfig) { c.ColumnsOrder = c.ColumnsOrder[:2] }},
+		{"unknown in order", func(c *TableConfig) { c.ColumnsOrder = append(c.ColumnsOrder, "amount") }},
+		{"listed twice", func(c *TableConfig) { c.ColumnsOrder = append(c.ColumnsOrder, "id") }},
+		{"same target", func(c *TableConfig) {
+			c.Columns["name"] = ColumnInfo{TargetName: "ID", Type: "VARCHAR2", Length: 40, Create: true}
+		}},
+		{"reserved target", func(c *TableConfig) {
+			c.Columns["name"] = ColumnInfo{TargetName: "date", Type: "VARCHAR2", Length: 40, Create: true}
+		}},
+		{"long target", func(c *TableConfig) {
+			c.Columns["name"] = ColumnInfo{TargetName: "a_very_long_column_name_over_thirty_bytes", Type: "VARCHAR2", Length: 40, Create: true}
+		}},
+		{"key on dropped column", func(c *TableConfig) { c.Keys[0].Columns = []string{"empty1"} }},
+		{"key type", func(c *TableConfig) { c.Keys[0].Type = "FOREIGN KEY" }},
+		{"key on LOB", func(c *TableConfig) {
+			c.Columns["name"] = ColumnInfo{TargetName: "client_name", Type: "CLOB", Length: 5000, Create: true}
+			c.Keys[0].Columns = []string{"name"}
+		}},
+		{"long key name", func(c *TableConfig) { c.Keys[0].Name = "pk_monthly_bank_statement_payments" }},
+		{"two primary keys", func(c *TableConfig) {
+			c.Keys = append(c.Keys, KeyConstraint{Type: "PRIMARY KEY", Columns: []string{"name"}})
+		}},
+		{"owner", func(c *TableConfig) { c.Metadata.Owner = "sales.eu" }},
+		{"tablespace", func(c *TableConfig) { c.Metadata.Tablespace = "1users" }},
+		{"pctfree", func(c *TableConfig) { pctFree := 100; c.Metadata.PctFree = &pctFree }},
+	}
+
+	for _, tt := range tests {
+		tableConfig := valid()
+		tt.modify(tableConfig)
+		if err := tableConfig.Validate(); err == nil {
+			t.Errorf("Expected an error for %s", tt.name)
+		}
+	}
+}
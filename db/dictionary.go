@@ -0,0 +1,119 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
+)
+
+// ColumnSpec overrides what was inferred for a column. Empty fields keep
+// the inferred values.
+type ColumnSpec struct {
+	Type      string `json:"type,omitempty"`
+	Length    int    `json:"length,omitempty"`
+	Precision int    `json:"precision,omitempty"`
+	Scale     int    `json:"scale,omitempty"`
+	Format    string `json:"format,omitempty"`
+}
+
+// DictionaryEntry names the columns of a header, given exactly or as a
+// regular expression, and may fix their type.
+type DictionaryEntry struct {
+	Header  string `json:"header,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Name    string `json:"name,omitempty"`
+	ColumnSpec
+
+	re *regexp.Regexp
+}
+
+// Dictionary maps the headers a team keeps receiving to the same column
+// names and types across tables.
+type Dictionary []DictionaryEntry
+
+// LoadDictionary reads a dictionary from a JSON file holding a list of
+// entries.
+func LoadDictionary(filePath string) (Dictionary, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header dictionary: %v", err)
+	}
+	var dictionary Dictionary
+	if err := json.Unmarshal(data, &dictionary); err != nil {
+		return nil, fmt.Errorf("error unmarshalling header dictionary: %v", err)
+	}
+
+	for i := range dictionary {
+		entry := &dictionary[i]
+		if (entry.Header == "") == (entry.Pattern == "") {
+			return nil, fmt.Errorf("header dictionary entry %d needs either a header or a pattern", i+1)
+		}
+		if entry.Name == "" && entry.Type == "" {
+			return nil, fmt.Errorf("header dictionary entry %d sets neither a name nor a type", i+1)
+		}
+		// Names are used as they are, so they must be valid without quotes
+		if entry.Name != "" && !util.IsUnquotedIdentifier(entry.Name) {
+			return nil, fmt.Errorf("header dictionary entry %d (%s) has name %q, which is not a valid Oracle identifier", i+1, entry.describe(), entry.Name)
+		}
+		if entry.Pattern != "" {
+			if entry.re, err = regexp.Compile(entry.Pattern); err != nil {
+				return nil, fmt.Errorf("invalid pattern in header dictionary entry %d: %v", i+1, err)
+			}
+		}
+	}
+	return dictionary, nil
+}
+
+// Lookup finds the entry of a header. Headers are compared ignoring case
+// and repeated spaces; exact headers win over patterns, and patterns are
+// tried in file order.
+func (d Dictionary) Lookup(header string) (DictionaryEntry, bool) {
+	normalized := strings.Join(strings.Fields(header), " ")
+	for _, entry := range d {
+		if entry.Header != "" && strings.EqualFold(strings.Join(strings.Fields(entry.Header), " "), normalized) {
+			return entry, true
+		}
+	}
+	for _, entry := range d {
+		if entry.re != nil && entry.re.MatchString(header) {
+			return entry, true
+		}
+	}
+	return DictionaryEntry{}, false
+}
+
+// describe identifies the entry in type reasons.
+func (e DictionaryEntry) describe() string {
+	if e.Header != "" {
+		return fmt.Sprintf("header dictionary entry %q", e.Header)
+	}
+	return fmt.Sprintf("header dictionary pattern /%s/", e.Pattern)
+}
+
+// apply overrides the inferred type of a column. Details that only made
+// sense for the inferred type are dropped when the type changes.
+func (s ColumnSpec) apply(colInfo *ColumnInfo, reason string) {
+	if s.Type != "" && !strings.EqualFold(s.Type, colInfo.Type) {
+		colInfo.Type = strings.ToUpper(s.Type)
+		colInfo.Kind = ""
+		colInfo.Precision, colInfo.Scale, colInfo.Format = 0, 0, ""
+		colInfo.NumericLocale = nil
+		colInfo.CheckDomain = false
+	}
+	if s.Length > 0 {
+		colInfo.Length = s.Length
+	}
+	if s.Precision > 0 {
+		colInfo.Precision, colInfo.Scale = s.Precision, s.Scale
+	}
+	if s.Format != "" {
+		colInfo.Format = s.Format
+	}
+	if s != (ColumnSpec{}) {
+		colInfo.TypeReason = "set by " + reason
+	}
+}
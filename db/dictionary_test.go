@@ -0,0 +1,75 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	filePath := filepath.Join(dir, name)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return filePath
+}
+
+func TestGenerateTableConfig_HeaderDictionary(t *testing.T) {
+	dir := t.TempDir()
+	dictionary := writeFile(t, dir, "headers.json", `[
+  {"header": "код  єдрпоу", "name": "edrpou", "type": "VARCHAR2", "length": 10},
+  {"pattern": "(?i)^дата опер", "name": "op_date"},
+  {"header": "Сума", "name": "amount", "type": "NUMBER", "precision": 15, "scale": 2}
+]`)
+	csvFile := writeFile(t, dir, "data.csv", "Код ЄДРПОУ;Дата операції;Сума;Назва\n"+
+		"00123456;31.12.2023;1 234,50;Тест\n"+
+		"12345678;01.01.2024;12,00;Ще\n")
+
+	tableConfig, err := GenerateTableConfig(csvFile, "payments", ';', GenerateOptions{HeaderDictionary: dictionary})
+	if err != nil {
+		t.Fatalf("Failed to generate table config: %v", err)
+	}
+
+	expectedOrder := []string{"edrpou", "op_date", "amount", "nazva"}
+	for i, colName := range expectedOrder {
+		if tableConfig.ColumnsOrder[i] != colName {
+			t.Errorf("Expected column %d to be %s but got %s", i+1, colName, tableConfig.ColumnsOrder[i])
+		}
+	}
+
+	edrpou := tableConfig.Columns["edrpou"]
+	if edrpou.Type != "VARCHAR2" || edrpou.Length != 10 || edrpou.TypeReason != `set by header dictionary entry "код  єдрпоу"` {
+		t.Errorf("Expected VARCHAR2(10) set by the dictionary but got %s(%d): %s", edrpou.Type, edrpou.Length, edrpou.TypeReason)
+	}
+	opDate := tableConfig.Columns["op_date"]
+	if opDate.Type != "DATE" || opDate.Format != "DD.MM.YYYY" || opDate.TypeReason != "" {
+		t.Errorf("Expected the inferred DATE DD.MM.YYYY but got %s %s: %s", opDate.Type, opDate.Format, opDate.TypeReason)
+	}
+	amount := tableConfig.Columns["amount"]
+	if amount.Precision != 15 || amount.Scale != 2 || amount.NumericLocale == nil {
+		t.Errorf("Expected NUMBER(15,2) keeping the numeric locale but got NUMBER(%d,%d) with %v", amount.Precision, amount.Scale, amount.NumericLocale)
+	}
+	if tableConfig.Metadata.HeaderDictionary != dictionary {
+		t.Errorf("Expected the dictionary to be recorded but got %q", tableConfig.Metadata.HeaderDictionary)
+	}
+}
+
+func TestLoadDictionary_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := []string{
+		`[{"name": "edrpou"}]`,
+		`[{"header": "Сума", "pattern": "сума", "name": "amount"}]`,
+		`[{"header": "Сума"}]`,
+		`[{"pattern": "(", "name": "amount"}]`,
+		`{"Сума": "amount"}`,
+		`[{"header": "Сума", "name": "сума"}]`,
+		`[{"header": "Сума", "name": "total amount"}]`,
+		`[{"header": "Дата", "name": "date"}]`,
+	}
+
+	for _, content := range tests {
+		if _, err := LoadDictionary(writeFile(t, dir, "headers.json", content)); err == nil {
+			t.Errorf("Expected an error for %s", content)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
+)
+
+// Change actions, in the notation of terraform plan.
+const (
+	ActionAdd    = "+"
+	ActionModify = "~"
+	ActionRemove = "-"
+)
+
+var typePrecision = regexp.MustCompile(`\(\d+\)`)
+
+// TableColumn is a column of an existing table, as read from
+// ALL_TAB_COLUMNS.
+type TableColumn struct {
+	Name string
+	// Type is the data type without fractional second precision, such as
+	// TIMESTAMP WITH TIME ZONE.
+	Type string
+	// Semantics is BYTE or CHAR for VARCHAR2 and CHAR columns.
+	Semantics string
+	// Length is in characters for columns with CHAR length semantics and in
+	// bytes otherwise.
+	Length    int
+	Precision int
+	Scale     int
+	Nullable  bool
+}
+
+// TypeSQL returns the data type of the column as written in DDL.
+func (c TableColumn) TypeSQL() string {
+	switch c.Type {
+	case "VARCHAR2", "CHAR":
+		if c.Semantics != "" {
+			return fmt.Sprintf("%s(%d %s)", c.Type, c.Length, c.Semantics)
+		}
+		return fmt.Sprintf("%s(%d)", c.Type, c.Length)
+	case "NVARCHAR2", "NCHAR", "RAW":
+		return fmt.Sprintf("%s(%d)", c.Type, c.Length)
+	case "NUMBER":
+		if c.Precision == 0 {
+			return c.Type
+		}
+		if c.Scale == 0 {
+			return fmt.Sprintf("NUMBER(%d)", c.Precision)
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", c.Precision, c.Scale)
+	default:
+		return c.Type
+	}
+}
+
+// Change is a difference between the table config and an existing table.
+// SQL is empty for differences that are only reported.
+type Change struct {
+	Action string
+	Column string
+	From   string
+	To     string
+	Note   string
+	SQL    string
+}
+
+func (c Change) String() string {
+	var s string
+	switch c.Action {
+	case ActionAdd:
+		s = fmt.Sprintf("  + %s %s", c.Column, c.To)
+	case ActionModify:
+		s = fmt.Sprintf("  ~ %s %s -> %s", c.Column, c.From, c.To)
+	default:
+		s = fmt.Sprintf("  - %s %s", c.Column, c.From)
+	}
+	if c.Note != "" {
+		s += " (" + c.Note + ")"
+	}
+	return s
+}
+
+// ReadTableColumns reads the columns of a table in column order, looking in
+// the current schema when owner is empty. It returns no columns when the
+// table does not exist.
+func ReadTableColumns(db *sql.DB, owner, tableName string) ([]TableColumn, error) {
+	query := `
+	SELECT column_name, data_type, data_length, char_length, char_used,
+	       data_precision, data_scale, nullable
+	FROM all_tab_columns
+	WHERE owner = NVL(:1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA'))
+	AND table_name = :2
+	ORDER BY column_id
+	`
+	rows, err := db.Query(query, strings.ToUpper(owner), strings.ToUpper(tableName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading table columns: %v", err)
+	}
+	defer rows.Close()
+
+	var columns []TableColumn
+	for rows.Next() {
+		var column TableColumn
+		var dataLength, charLength int
+		var charUsed, nullable sql.NullString
+		var precision, scale sql.NullInt64
+		err := rows.Scan(&column.Name, &column.Type, &dataLength, &charLength, &charUsed, &precision, &scale, &nullable)
+		if err != nil {
+			return nil, fmt.Errorf("error reading table columns: %v", err)
+		}
+		column.Type = typePrecision.ReplaceAllString(column.Type, "")
+		column.Length = dataLength
+		if charUsed.String == "C" {
+			column.Length = charLength
+		}
+		if column.Type == "VARCHAR2" || column.Type == "CHAR" {
+			switch charUsed.String {
+			case "B":
+				column.Semantics = "BYTE"
+			case "C":
+				column.Semantics = "CHAR"
+			}
+		}
+		column.Precision, column.Scale = int(precision.Int64), int(scale.Int64)
+		column.Nullable = nullable.String == "Y"
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading table columns: %v", err)
+	}
+	return columns, nil
+}
+
+// DiffTable compares the columns created by a table config with those of
+// the existing table. New columns are added and narrower ones widened;
+// other differences and columns missing from the config are only reported.
+func DiffTable(tableConfig *TableConfig, existing []TableColumn) []Change {
+	tableName := tableConfig.Metadata.QualifiedTableName()
+	byName := make(map[string]TableColumn, len(existing))
+	for _, column := range existing {
+		byName[column.Name] = column
+	}
+
+	var changes []Change
+	seen := make(map[string]bool)
+	for _, colName := range tableConfig.ColumnsOrder {
+		colInfo := tableConfig.Columns[colName]
+		if !colInfo.Create {
+			continue
+		}
+		name := tableConfig.storedName(colName)
+		seen[name] = true
+		columnType, _ := colInfo.ColumnType(tableConfig.Metadata)
+		planned := columnType.SQL()
+
+		column, ok := byName[name]
+		if !ok {
+			changes = append(changes, Change{
+				Action: ActionAdd,
+				Column: tableConfig.TargetName(colName),
+				To:     planned,
+				SQL:    fmt.Sprintf("ALTER TABLE %s ADD (%s %s)", tableName, tableConfig.ColumnSQL(colName), planned),
+			})
+			continue
+		}
+
+		widened, compatible := compareColumn(columnType, column, colInfo.byteLength())
+		switch {
+		case !compatible:
+			changes = append(changes, Change{
+				Action: ActionModify,
+				Column: tableConfig.TargetName(colName),
+				From:   column.TypeSQL(),
+				To:     planned,
+				Note:   "different type, change it by hand",
+			})
+		case widened != nil:
+			modified := widened.SQL()
+			changes = append(changes, Change{
+				Action: ActionModify,
+				Column: tableConfig.TargetName(colName),
+				From:   column.TypeSQL(),
+				To:     modified,
+				SQL:    fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s)", tableName, tableConfig.ColumnSQL(colName), modified),
+			})
+		}
+	}
+
+	for _, column := range existing {
+		if !seen[column.Name] {
+			note := "not in the config, left in place"
+			if !column.Nullable {
+				note = "not in the config and NOT NULL, the load will fail"
+			}
+			changes = append(changes, Change{Action: ActionRemove, Column: column.Name, From: column.TypeSQL(), Note: note})
+		}
+	}
+	return changes
+}
+
+// storedName returns the target name of a column as Oracle stores it:
+// unquoted names in upper case.
+func (t *TableConfig) storedName(colName string) string {
+	if t.Metadata.NamingStrategy == util.NamingPassthrough {
+		return t.TargetName(colName)
+	}
+	return strings.ToUpper(t.TargetName(colName))
+}
+
+// byteLength returns the length of the longest value in bytes, or in
+// characters for configs written before it was recorded.
+func (c ColumnInfo) byteLength() int {
+	return max(c.Bytes, c.Length)
+}
+
+// compareColumn checks whether an existing column can hold the values of a
+// configured one, whose longest value takes bytes bytes. Dates fit
+// timestamps and text fits LOBs. When it is compatible but too narrow,
+// widened is the configured type resized to hold both.
+func compareColumn(columnType ColumnType, column TableColumn, bytes int) (widened *ColumnType, compatible bool) {
+	switch columnType.Name {
+	case "NUMBER", "NUMERIC":
+		if column.Type != "NUMBER" && column.Type != "FLOAT" {
+			return nil, false
+		}
+		// An unconstrained NUMBER holds any number
+		if column.Precision == 0 || column.Type == "FLOAT" {
+			return nil, true
+		}
+		if columnType.Precision == 0 {
+			resized := columnType
+			return &resized, true
+		}
+		intDigits := max(columnType.Precision-columnType.Scale, column.Precision-column.Scale)
+		scale := max(columnType.Scale, column.Scale)
+		if intDigits+scale == column.Precision && scale == column.Scale {
+			return nil, true
+		}
+		resized := columnType
+		resized.Precision, resized.Scale = intDigits+scale, scale
+		return &resized, true
+	case "INTEGER":
+		// INTEGER is stored as NUMBER(*,0)
+		return nil, column.Type == "NUMBER" && column.Precision == 0 || column.Type == "FLOAT"
+	case "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE":
+		switch column.Type {
+		case "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE":
+			return nil, true
+		}
+		return nil, column.Type == "NUMBER" && column.Precision == 0
+	case "DATE":
+		return nil, column.Type == "DATE" || strings.HasPrefix(column.Type, "TIMESTAMP")
+	case "TIMESTAMP":
+		return nil, strings.HasPrefix(column.Type, "TIMESTAMP")
+	case "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		return nil, column.Type == columnType.Name
+	case "CLOB", "NCLOB":
+		return nil, column.Type == "CLOB" || column.Type == "NCLOB"
+	case "RAW":
+		if column.Type != "RAW" {
+			return nil, false
+		}
+	default:
+		// Any text fits a LOB
+		switch column.Type {
+		case "CLOB", "NCLOB":
+			return nil, true
+		case "CHAR", "NCHAR":
+			if columnType.Name != "CHAR" && columnType.Name != "NCHAR" {
+				return nil, false
+			}
+		case "VARCHAR2", "NVARCHAR2":
+			if columnType.Name == "CHAR" || columnType.Name == "NCHAR" {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+		if column.Semantics == "BYTE" && columnType.Semantics != "BYTE" {
+			// The config counts characters, and the values may need more
+			// bytes than that
+			if columnType.Length <= column.Length && bytes <= column.Length {
+				return nil, true
+			}
+			resized := columnType
+			resized.Semantics = "CHAR"
+			return &resized, true
+		}
+	}
+	if columnType.Length <= column.Length {
+		return nil, true
+	}
+	return &columnType, true
+}
+
+// PlanTableChanges diffs a table config against the table in the database.
+// exists is false when the table has yet to be created.
+func PlanTableChanges(user, password, dsn string, tableConfig *TableConfig) (changes []Change, exists bool, err error) {
+	db, err := openDB(user, password, dsn)
+	if err != nil {
+		return nil, false, err
+	}
+	defer db.Close()
+
+	columns, err := ReadTableColumns(db, tableConfig.Metadata.Owner, tableConfig.Metadata.TableName)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(columns) == 0 {
+		return nil, false, nil
+	}
+	return DiffTable(tableConfig, columns), true, nil
+}
+
+// AlterTable runs the statements of the changes that have one.
+func AlterTable(user, password, dsn string, changes []Change) error {
+	db, err := openDB(user, password, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, change := range changes {
+		if change.SQL == "" {
+			continue
+		}
+		log.Printf("Altering table: %s", change.SQL)
+		if _, err := db.Exec(change.SQL); err != nil {
+			return fmt.Errorf("error altering column %s: %v", change.Column, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffTable(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"id":      {Type: "NUMBER", Precision: 10, Create: true},
+			"amount":  {Type: "NUMBER", Precision: 12, Scale: 3, Create: true},
+			"name":    {Type: "VARCHAR2", Length: 80, Create: true},
+			"code":    {Type: "VARCHAR2", Length: 8, Create: true},
+			"paid_on": {Type: "DATE", Create: true},
+			"note":    {Type: "CLOB", Length: 5000, Create: true},
+			"city":    {Type: "VARCHAR2", Length: 20, Bytes: 38, Create: true},
+			"country": {Type: "VARCHAR2", Length: 20, Bytes: 20, Create: true},
+			"empty1":  {Type: "VARCHAR2", Length: 1, Create: false},
+		},
+		Metadata:     Metadata{TableName: "payments"},
+		ColumnsOrder: []string{"id", "amount", "name", "code", "paid_on", "note", "city", "country", "empty1"},
+	}
+	existing := []TableColumn{
+		{Name: "ID", Type: "NUMBER"},
+		{Name: "AMOUNT", Type: "NUMBER", Precision: 10, Scale: 2},
+		{Name: "NAME", Type: "VARCHAR2", Length: 50},
+		{Name: "CODE", Type: "VARCHAR2", Length: 10},
+		{Name: "PAID_ON", Type: "VARCHAR2", Length: 10, Nullable: true},
+		{Name: "CITY", Type: "VARCHAR2", Length: 20, Semantics: "BYTE"},
+		{Name: "COUNTRY", Type: "VARCHAR2", Length: 20, Semantics: "BYTE"},
+		{Name: "LEGACY", Type: "VARCHAR2", Length: 20, Nullable: true},
+		{Name: "BATCH_ID", Type: "NUMBER", Precision: 10},
+	}
+
+	expected := []Change{
+		{Action: ActionModify, Column: "amount", From: "NUMBER(10,2)", To: "NUMBER(12,3)", SQL: "ALTER TABLE payments MODIFY (amount NUMBER(12,3))"},
+		{Action: ActionModify, Column: "name", From: "VARCHAR2(50)", To: "VARCHAR2(80)", SQL: "ALTER TABLE payments MODIFY (name VARCHAR2(80))"},
+		{Action: ActionModify, Column: "paid_on", From: "VARCHAR2(10)", To: "DATE", Note: "different type, change it by hand"},
+		{Action: ActionAdd, Column: "note", To: "CLOB", SQL: "ALTER TABLE payments ADD (note CLOB)"},
+		{Action: ActionModify, Column: "city", From: "VARCHAR2(20 BYTE)", To: "VARCHAR2(20 CHAR)", SQL: "ALTER TABLE payments MODIFY (city VARCHAR2(20 CHAR))"},
+		{Action: ActionRemove, Column: "LEGACY", From: "VARCHAR2(20)", Note: "not in the config, left in place"},
+		{Action: ActionRemove, Column: "BATCH_ID", From: "NUMBER(10)", Note: "not in the config and NOT NULL, the load will fail"},
+	}
+
+	result := DiffTable(tableConfig, existing)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected\n%+v\nbut got\n%+v", expected, result)
+	}
+
+	expectedLines := []string{
+		"  ~ amount NUMBER(10,2) -> NUMBER(12,3)",
+		"  + note CLOB",
+		"  ~ city VARCHAR2(20 BYTE) -> VARCHAR2(20 CHAR)",
+		"  - LEGACY VARCHAR2(20) (not in the config, left in place)",
+	}
+	for i, change := range []Change{result[0], result[3], result[4], result[5]} {
+		if change.String() != expectedLines[i] {
+			t.Errorf("Expected %q but got %q", expectedLines[i], change.String())
+		}
+	}
+}
+
+func TestCheckTable(t *testing.T) {
+	tableConfig := &TableConfig{
+		Columns: map[string]ColumnInfo{
+			"id":      {Type: "NUMBER", Precision: 10, Create: true},
+			"amount":  {Type: "NUMBER", Precision: 12, Scale: 2, Create: true},
+			"name":    {Type: "VARCHAR2", Length: 80, Create: true},
+			"paid_on": {Type: "DATE", Create: true},
+			"note":    {Type: "VARCHAR2", Length: 20, Create: true},
+			"created": {Type: "DATE", Create: true},
+			"remarks": {Type: "VARCHAR2", Length: 5000, Create: true},
+			"flag":    {Type: "CHAR", Length: 1, Create: true},
+			"code":    {Type: "VARCHAR2", Length: 3, Create: true},
+			"total":   {Type: "NUMBER", Create: true},
+			"city":    {Type: "VARCHAR2", Length: 20, Bytes: 38, Create: true},
+			"region":  {Type: "VARCHAR2", Length: 20, Bytes: 38, Create: true},
+			"street":  {Type: "VARCHAR2", Length: 20, Bytes: 38, Create: true},
+			"empty1":  {Type: "VARCHAR2", Length: 1, Create: false},
+		},
+		Metadata:     Metadata{TableName: "payments"},
+		ColumnsOrder: []string{"id", "amount", "name", "paid_on", "note", "created", "remarks", "flag", "code", "total", "city", "region", "street", "empty1"},
+	}
+	existing := []TableColumn{
+		{Name: "ID", Type: "NUMBER", Precision: 12},
+		{Name: "AMOUNT", Type: "NUMBER", Precision: 10, Scale: 2},
+		{Name: "NAME", Type: "VARCHAR2", Length: 50},
+		{Name: "PAID_ON", Type: "NUMBER"},
+		{Name: "CREATED", Type: "TIMESTAMP WITH TIME ZONE"},
+		{Name: "REMARKS", Type: "CLOB"},
+		{Name: "FLAG", Type: "VARCHAR2", Length: 1},
+		{Name: "CODE", Type: "CHAR", Length: 10},
+		{Name: "TOTAL", Type: "NUMBER", Precision: 10},
+		{Name: "CITY", Type: "VARCHAR2", Length: 20, Semantics: "BYTE"},
+		{Name: "REGION", Type: "VARCHAR2", Length: 40, Semantics: "BYTE"},
+		{Name: "STREET", Type: "VARCHAR2", Length: 20, Semantics: "CHAR"},
+		{Name: "LEGACY", Type: "VARCHAR2", Length: 20, Nullable: true},
+		{Name: "BATCH_ID", Type: "NUMBER", Precision: 10},
+	}
+
+	expected := []Mismatch{
+		{Column: "amount", Problem: "NUMBER(10,2) is too small for values with 10 integer digits"},
+		{Column: "name", Problem: "VARCHAR2(50) is too narrow for values of 80 characters"},
+		{Column: "paid_on", Problem: "NUMBER in the table, DATE in the config"},
+		{Column: "note", Problem: "missing from the table"},
+		{Column: "city", Problem: "VARCHAR2(20 BYTE) is too narrow for values of 38 bytes"},
+		{Column: "BATCH_ID", Problem: "NOT NULL but not in the config"},
+	}
+
+	result := CheckTable(tableConfig, existing)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected\n%+v\nbut got\n%+v", expected, result)
+	}
+}
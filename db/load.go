@@ -0,0 +1,86 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Load modes. INSERT, APPEND, TRUNCATE and REPLACE are the SQL*Loader
+// methods; RECREATE drops and creates the table before an INSERT.
+const (
+	LoadInsert   = "INSERT"
+	LoadAppend   = "APPEND"
+	LoadTruncate = "TRUNCATE"
+	LoadReplace  = "REPLACE"
+	LoadRecreate = "RECREATE"
+)
+
+// ParseLoadMode checks a load mode, ignoring case. The empty mode is
+// INSERT, which only loads into an empty table.
+func ParseLoadMode(mode string) (string, error) {
+	switch mode = strings.ToUpper(mode); mode {
+	case "":
+		return LoadInsert, nil
+	case LoadInsert, LoadAppend, LoadTruncate, LoadReplace, LoadRecreate:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid load mode %q, expected INSERT, APPEND, TRUNCATE, REPLACE or RECREATE", mode)
+	}
+}
+
+// IsDestructiveLoadMode reports whether a load mode deletes the rows
+// already in the table.
+func IsDestructiveLoadMode(mode string) bool {
+	return mode == LoadTruncate || mode == LoadReplace || mode == LoadRecreate
+}
+
+// LoadMethod returns the SQL*Loader method of the table's load mode.
+func (m Metadata) LoadMethod() string {
+	mode, err := ParseLoadMode(m.LoadMode)
+	if err != nil || mode == LoadRecreate {
+		// The recreated table is empty
+		return LoadInsert
+	}
+	return mode
+}
+
+// CountRows returns the number of rows in the table of the metadata, or -1
+// when it does not exist.
+func CountRows(user, password, dsn string, metadata Metadata) (int, error) {
+	db, err := openDB(user, password, dsn)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	tableExists, err := checkIfTableExists(db, metadata.Owner, metadata.TableName)
+	if err != nil {
+		return 0, fmt.Errorf("error checking if table exists: %v", err)
+	}
+	if !tableExists {
+		return -1, nil
+	}
+
+	var count int
+	err = db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", metadata.QualifiedTableName())).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting rows: %v", err)
+	}
+	return count, nil
+}
+
+// DropTable drops the table of the metadata with its data. The table goes
+// to the recycle bin, so it can still be restored with FLASHBACK TABLE.
+func DropTable(user, password, dsn string, metadata Metadata) error {
+	db, err := openDB(user, password, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(fmt.Sprintf("DROP TABLE %s", metadata.QualifiedTableName()))
+	if err != nil {
+		return fmt.Errorf("error dropping table: %v", err)
+	}
+	return nil
+}
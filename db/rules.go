@@ -0,0 +1,101 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule overrides inference for the columns whose original header and
+// generated name match its patterns. Patterns are written /re/ or /re/i.
+type Rule struct {
+	Header string `json:"header,omitempty"`
+	Name   string `json:"name,omitempty"`
+	ColumnSpec
+	Create *bool `json:"create,omitempty"`
+
+	header, name *regexp.Regexp
+}
+
+// Rules are applied in file order, so a later rule wins over an earlier one.
+type Rules []Rule
+
+// LoadRules reads rules from a JSON file holding a list of rules.
+func LoadRules(filePath string) (Rules, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header rules: %v", err)
+	}
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error unmarshalling header rules: %v", err)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Header == "" && rule.Name == "" {
+			return nil, fmt.Errorf("header rule %d needs a header or a name pattern", i+1)
+		}
+		if rule.ColumnSpec == (ColumnSpec{}) && rule.Create == nil {
+			return nil, fmt.Errorf("header rule %d changes nothing", i+1)
+		}
+		if rule.header, err = compilePattern(rule.Header); err != nil {
+			return nil, fmt.Errorf("invalid header pattern in header rule %d: %v", i+1, err)
+		}
+		if rule.name, err = compilePattern(rule.Name); err != nil {
+			return nil, fmt.Errorf("invalid name pattern in header rule %d: %v", i+1, err)
+		}
+	}
+	return rules, nil
+}
+
+// compilePattern compiles a /re/ or /re/i pattern. A pattern without
+// slashes is a plain regular expression.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	expr := pattern
+	if end := strings.LastIndex(pattern, "/"); strings.HasPrefix(pattern, "/") && end > 0 {
+		expr = pattern[1:end]
+		switch flags := pattern[end+1:]; flags {
+		case "":
+		case "i":
+			expr = "(?i)" + expr
+		default:
+			return nil, fmt.Errorf("unsupported flags %q in %s", flags, pattern)
+		}
+	}
+	return regexp.Compile(expr)
+}
+
+// Apply runs the rules on a column and lists the ones that matched in
+// colInfo.Rules.
+func (r Rules) Apply(colName string, colInfo *ColumnInfo) {
+	for i, rule := range r {
+		if rule.header != nil && !rule.header.MatchString(colInfo.OriginalName) ||
+			rule.name != nil && !rule.name.MatchString(colName) {
+			continue
+		}
+		description := rule.describe(i)
+		rule.apply(colInfo, description)
+		if rule.Create != nil {
+			colInfo.Create = *rule.Create
+		}
+		colInfo.Rules = append(colInfo.Rules, description)
+	}
+}
+
+// describe identifies a rule by its position and patterns.
+func (r Rule) describe(i int) string {
+	var patterns []string
+	if r.Header != "" {
+		patterns = append(patterns, "header "+r.Header)
+	}
+	if r.Name != "" {
+		patterns = append(patterns, "name "+r.Name)
+	}
+	return fmt.Sprintf("header rule %d (%s)", i+1, strings.Join(patterns, ", "))
+}
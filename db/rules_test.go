@@ -0,0 +1,75 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateTableConfig_HeaderRules(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `[
+  {"header": "/дата/i", "type": "DATE", "format": "DD.MM.YYYY"},
+  {"name": "/^empty\\d+$/", "create": false},
+  {"header": "/коментар/", "type": "VARCHAR2", "length": 4000},
+  {"header": "/^Дата/", "name": "/opla/", "format": "DD/MM/YYYY"}
+]`)
+	csvFile := writeFile(t, dir, "data.csv", "Дата оплати;Коментар;ДАТА;;\n"+
+		"31/12/2023;ok;2024-01-31;x;y\n")
+
+	tableConfig, err := GenerateTableConfig(csvFile, "payments", ';', GenerateOptions{HeaderRules: rules})
+	if err != nil {
+		t.Fatalf("Failed to generate table config: %v", err)
+	}
+
+	tests := []struct {
+		colName string
+		typ     string
+		length  int
+		format  string
+		create  bool
+		rules   []string
+	}{
+		{"data_oplati", "DATE", 10, "DD/MM/YYYY", true, []string{"header rule 1 (header /дата/i)", "header rule 4 (header /^Дата/, name /opla/)"}},
+		{"komentar", "VARCHAR2", 2, "", true, nil},
+		{"data", "DATE", 10, "DD.MM.YYYY", true, []string{"header rule 1 (header /дата/i)"}},
+		{"empty1", "VARCHAR2", 1, "", false, []string{"header rule 2 (name /^empty\\d+$/)"}},
+	}
+
+	for _, tt := range tests {
+		colInfo := tableConfig.Columns[tt.colName]
+		if colInfo.Type != tt.typ || colInfo.Length != tt.length || colInfo.Format != tt.format || colInfo.Create != tt.create || !reflect.DeepEqual(colInfo.Rules, tt.rules) {
+			t.Errorf("Expected %s %s(%d) %q create=%v by %v but got %s(%d) %q create=%v by %v",
+				tt.colName, tt.typ, tt.length, tt.format, tt.create, tt.rules,
+				colInfo.Type, colInfo.Length, colInfo.Format, colInfo.Create, colInfo.Rules)
+		}
+	}
+	if reason := tableConfig.Columns["data_oplati"].TypeReason; reason != "set by header rule 4 (header /^Дата/, name /opla/)" {
+		t.Errorf("Expected the last rule as the type reason but got %q", reason)
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		matches bool
+	}{
+		{"/дата/i", "ДАТА операції", true},
+		{"/дата/", "ДАТА операції", false},
+		{"^сума$", "сума", true},
+		{"/a/b/", "a/b", true},
+	}
+
+	for _, tt := range tests {
+		re, err := compilePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("Failed to compile %s: %v", tt.pattern, err)
+		}
+		if re.MatchString(tt.value) != tt.matches {
+			t.Errorf("Expected %s to match %q: %v", tt.pattern, tt.value, tt.matches)
+		}
+	}
+	if _, err := compilePattern("/дата/g"); err == nil {
+		t.Errorf("Expected an error for unsupported flags")
+	}
+}
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ColumnType is the Oracle data type of a column with its parameters.
+type ColumnType struct {
+	// Name is the data type without parameters, such as VARCHAR2 or
+	// TIMESTAMP WITH TIME ZONE.
+	Name string
+	// Length is the size of VARCHAR2, NVARCHAR2, CHAR, NCHAR and RAW
+	// columns.
+	Length int
+	// Semantics is BYTE or CHAR for VARCHAR2 and CHAR columns, the session
+	// default when empty.
+	Semantics string
+	// Precision and Scale are those of NUMBER and NUMERIC columns, where a
+	// zero precision means any number. Precision is the binary precision of
+	// FLOAT columns.
+	Precision int
+	Scale     int
+	// FractionalSeconds is the precision of TIMESTAMP columns, the Oracle
+	// default of 6 when nil.
+	FractionalSeconds *int
+}
+
+// Limits of the data types that do not depend on MAX_STRING_SIZE.
+const (
+	maxCharLength      = 2000
+	maxNCharLength     = 1000
+	maxStandardRaw     = 2000
+	maxNumberPrecision = 38
+	maxFloatPrecision  = 126
+	maxFractionalSecs  = 9
+)
+
+var (
+	typePattern = regexp.MustCompile(`^([A-Z0-9_]+)(?: ?\(([^)]*)\))?( WITH(?: LOCAL)? TIME ZONE)?$`)
+	spaces      = regexp.MustCompile(`\s+`)
+)
+
+// ParseType parses a data type as written in the config: a type name,
+// optionally followed by its parameters, such as VARCHAR2(100 CHAR),
+// NUMBER(12,2) or TIMESTAMP(3) WITH TIME ZONE. Parameters that are not
+// written are zero.
+func ParseType(typ string) (ColumnType, error) {
+	normalized := spaces.ReplaceAllString(strings.ToUpper(strings.TrimSpace(typ)), " ")
+	match := typePattern.FindStringSubmatch(normalized)
+	if match == nil {
+		return ColumnType{}, fmt.Errorf("invalid type %q", typ)
+	}
+	columnType := ColumnType{Name: match[1] + match[3]}
+	params, hasParams := match[2], strings.Contains(normalized, "(")
+
+	switch columnType.Name {
+	case "VARCHAR2", "CHAR":
+		if !hasParams {
+			break
+		}
+		length, semantics, _ := strings.Cut(strings.TrimSpace(params), " ")
+		n, err := strconv.Atoi(length)
+		if err != nil {
+			return ColumnType{}, fmt.Errorf("invalid length in type %q", typ)
+		}
+		columnType.Length = n
+		switch semantics {
+		case "":
+		case "BYTE", "CHAR":
+			columnType.Semantics = semantics
+		default:
+			return ColumnType{}, fmt.Errorf("invalid length semantics in type %q, expected BYTE or CHAR", typ)
+		}
+	case "NVARCHAR2", "NCHAR", "RAW":
+		if !hasParams {
+			break
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(params))
+		if err != nil {
+			return ColumnType{}, fmt.Errorf("invalid length in type %q", typ)
+		}
+		columnType.Length = n
+	case "NUMBER", "NUMERIC":
+		if !hasParams {
+			break
+		}
+		precision, scale, hasScale := strings.Cut(params, ",")
+		p, err := strconv.Atoi(strings.TrimSpace(precision))
+		if err != nil || p < 1 {
+			return ColumnType{}, fmt.Errorf("invalid precision in type %q", typ)
+		}
+		columnType.Precision = p
+		if hasScale {
+			s, err := strconv.Atoi(strings.TrimSpace(scale))
+			if err != nil {
+				return ColumnType{}, fmt.Errorf("invalid scale in type %q", typ)
+			}
+			columnType.Scale = s
+		}
+	case "FLOAT":
+		if !hasParams {
+			break
+		}
+		p, err := strconv.Atoi(strings.TrimSpace(params))
+		if err != nil || p < 1 {
+			return ColumnType{}, fmt.Errorf("invalid precision in type %q", typ)
+		}
+		columnType.Precision = p
+	case "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		if !hasParams {
+			break
+		}
+		fsp, err := strconv.Atoi(strings.TrimSpace(params))
+		if err != nil {
+			return ColumnType{}, fmt.Errorf("invalid fractional seconds precision in type %q", typ)
+		}
+		columnType.FractionalSeconds = &fsp
+	case "INTEGER", "BINARY_FLOAT", "BINARY_DOUBLE", "DATE", "CLOB", "NCLOB":
+		if hasParams {
+			return ColumnType{}, fmt.Errorf("type %s takes no parameters", columnType.Name)
+		}
+	default:
+		return ColumnType{}, fmt.Errorf("unsupported type %q", typ)
+	}
+	return columnType, nil
+}
+
+// ColumnType parses the type of the column and fills in the parameters not
+// written in it: the length and precision of the column and the length
+// semantics of the table. It checks that they are within the limits of
+// the type and that the column holds the longest value in the file.
+// Types are checked when the config is loaded, so callers past that point
+// may ignore the error.
+func (c ColumnInfo) ColumnType(metadata Metadata) (ColumnType, error) {
+	columnType, err := ParseType(c.Type)
+	if err != nil {
+		return ColumnType{}, err
+	}
+
+	maxStringSize := metadata.MaxStringSize
+	if maxStringSize == 0 {
+		maxStringSize = StandardMaxStringSize
+	}
+	maxRaw := maxStandardRaw
+	if maxStringSize > StandardMaxStringSize {
+		maxRaw = maxStringSize
+	}
+
+	dataLength := valueLength(columnType.Name, c.Length)
+
+	var maxLength int
+	switch columnType.Name {
+	case "VARCHAR2":
+		maxLength = maxStringSize
+	case "NVARCHAR2":
+		// AL16UTF16 takes two bytes per character
+		maxLength = maxStringSize / 2
+	case "CHAR":
+		maxLength = maxCharLength
+	case "NCHAR":
+		maxLength = maxNCharLength
+	case "RAW":
+		maxLength = maxRaw
+	case "NUMBER", "NUMERIC":
+		if columnType.Precision == 0 && !strings.Contains(c.Type, "(") {
+			columnType.Precision, columnType.Scale = c.Precision, c.Scale
+		}
+		if columnType.Precision < 0 || columnType.Precision > maxNumberPrecision {
+			return ColumnType{}, fmt.Errorf("precision of %s must be between 1 and %d", columnType.SQL(), maxNumberPrecision)
+		}
+		if columnType.Precision > 0 && (columnType.Scale < -84 || columnType.Scale > 127) {
+			return ColumnType{}, fmt.Errorf("scale of %s must be between -84 and 127", columnType.SQL())
+		}
+	case "FLOAT":
+		if columnType.Precision < 0 || columnType.Precision > maxFloatPrecision {
+			return ColumnType{}, fmt.Errorf("precision of %s must be between 1 and %d", columnType.SQL(), maxFloatPrecision)
+		}
+	case "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		if fsp := columnType.FractionalSeconds; fsp != nil && (*fsp < 0 || *fsp > maxFractionalSecs) {
+			return ColumnType{}, fmt.Errorf("fractional seconds precision of %s must be between 0 and %d", columnType.SQL(), maxFractionalSecs)
+		}
+	}
+
+	if maxLength > 0 {
+		if columnType.Length == 0 {
+			columnType.Length = dataLength
+		}
+		if (columnType.Name == "VARCHAR2" || columnType.Name == "CHAR") && columnType.Semantics == "" {
+			columnType.Semantics = metadata.LengthSemantics
+		}
+		if columnType.Length < 1 || columnType.Length > maxLength {
+			return ColumnType{}, fmt.Errorf("length of %s must be between 1 and %d", columnType.SQL(), maxLength)
+		}
+		if columnType.Length < dataLength {
+			return ColumnType{}, fmt.Errorf("%s is too narrow for values of %d characters", columnType.SQL(), c.Length)
+		}
+	}
+	return columnType, nil
+}
+
+// valueLength returns the length a column of the type needs for values
+// of length characters in the file.
+func valueLength(typeName string, length int) int {
+	if typeName == "RAW" {
+		// A RAW value is half as long as its hexadecimal text
+		return max((length+1)/2, 1)
+	}
+	return max(length, 1)
+}
+
+// SQL returns the data type as written in DDL.
+func (t ColumnType) SQL() string {
+	switch t.Name {
+	case "VARCHAR2", "CHAR":
+		if t.Semantics != "" {
+			return fmt.Sprintf("%s(%d %s)", t.Name, t.Length, t.Semantics)
+		}
+		return fmt.Sprintf("%s(%d)", t.Name, t.Length)
+	case "NVARCHAR2", "NCHAR", "RAW":
+		return fmt.Sprintf("%s(%d)", t.Name, t.Length)
+	case "NUMBER", "NUMERIC":
+		if t.Precision == 0 {
+			return t.Name
+		}
+		if t.Scale == 0 {
+			return fmt.Sprintf("%s(%d)", t.Name, t.Precision)
+		}
+		return fmt.Sprintf("%s(%d,%d)", t.Name, t.Precision, t.Scale)
+	case "FLOAT":
+		if t.Precision == 0 {
+			return t.Name
+		}
+		return fmt.Sprintf("FLOAT(%d)", t.Precision)
+	case "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		if t.FractionalSeconds == nil {
+			return t.Name
+		}
+		return fmt.Sprintf("TIMESTAMP(%d)%s", *t.FractionalSeconds, strings.TrimPrefix(t.Name, "TIMESTAMP"))
+	default:
+		return t.Name
+	}
+}
+
+// IsNumeric reports whether the type holds numbers.
+func (t ColumnType) IsNumeric() bool {
+	switch t.Name {
+	case "NUMBER", "NUMERIC", "INTEGER", "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseType(t *testing.T) {
+	tests := map[string]string{
+		"varchar2(10)":                   "VARCHAR2(10)",
+		"VARCHAR2(100 CHAR)":             "VARCHAR2(100 CHAR)",
+		"CHAR (1)":                       "CHAR(1)",
+		"NVARCHAR2(50)":                  "NVARCHAR2(50)",
+		"NUMBER( 12, 2 )":                "NUMBER(12,2)",
+		"NUMBER":                         "NUMBER",
+		"INTEGER":                        "INTEGER",
+		"FLOAT(63)":                      "FLOAT(63)",
+		"BINARY_DOUBLE":                  "BINARY_DOUBLE",
+		"RAW(16)":                        "RAW(16)",
+		"TIMESTAMP(6)":                   "TIMESTAMP(6)",
+		"TIMESTAMP(0) WITH TIME ZONE":    "TIMESTAMP(0) WITH TIME ZONE",
+		"timestamp with local time zone": "TIMESTAMP WITH LOCAL TIME ZONE",
+		"DATE":                           "DATE",
+		"NCLOB":                          "NCLOB",
+	}
+	for typ, expected := range tests {
+		columnType, err := ParseType(typ)
+		if err != nil {
+			t.Errorf("Expected %s to parse but got %v", typ, err)
+			continue
+		}
+		if result := columnType.SQL(); result != expected {
+			t.Errorf("Expected %s but got %s", expected, result)
+		}
+	}
+
+	for _, typ := range []string{"", "TEXT", "VARCHAR2(abc)", "VARCHAR2(10 WORDS)", "NUMBER(0)", "DATE(7)", "CLOB(100)", "INTEGER(5)", "DATE WITH TIME ZONE", "TIMESTAMP WITH TIME ZONE(3)"} {
+		if _, err := ParseType(typ); err == nil {
+			t.Errorf("Expected an error for type %q", typ)
+		}
+	}
+}
+
+func TestColumnInfo_ColumnType(t *testing.T) {
+	metadata := Metadata{LengthSemantics: "CHAR"}
+	tests := []struct {
+		colInfo  ColumnInfo
+		expected string
+	}{
+		{ColumnInfo{Type: "VARCHAR2", Length: 80}, "VARCHAR2(80 CHAR)"},
+		{ColumnInfo{Type: "VARCHAR2(100 BYTE)", Length: 80}, "VARCHAR2(100 BYTE)"},
+		{ColumnInfo{Type: "CHAR", Length: 0}, "CHAR(1 CHAR)"},
+		{ColumnInfo{Type: "NVARCHAR2", Length: 20}, "NVARCHAR2(20)"},
+		{ColumnInfo{Type: "RAW", Length: 32}, "RAW(16)"},
+		{ColumnInfo{Type: "NUMBER", Length: 7, Precision: 6, Scale: 2}, "NUMBER(6,2)"},
+		{ColumnInfo{Type: "NUMBER(10)", Length: 7, Precision: 6, Scale: 2}, "NUMBER(10)"},
+		{ColumnInfo{Type: "FLOAT", Length: 7, Precision: 6}, "FLOAT"},
+		{ColumnInfo{Type: "TIMESTAMP(3)", Length: 23}, "TIMESTAMP(3)"},
+		{ColumnInfo{Type: "CLOB", Length: 5000}, "CLOB"},
+	}
+	for _, tt := range tests {
+		columnType, err := tt.colInfo.ColumnType(metadata)
+		if err != nil {
+			t.Errorf("Expected %s but got %v", tt.expected, err)
+			continue
+		}
+		if result := columnType.SQL(); result != tt.expected {
+			t.Errorf("Expected %s but got %s", tt.expected, result)
+		}
+	}
+
+	errors := []struct {
+		colInfo  ColumnInfo
+		expected string
+	}{
+		{ColumnInfo{Type: "VARCHAR2", Length: 5000}, "length of VARCHAR2(5000 CHAR) must be between 1 and 4000"},
+		{ColumnInfo{Type: "VARCHAR2(50)", Length: 80}, "VARCHAR2(50 CHAR) is too narrow for values of 80 characters"},
+		{ColumnInfo{Type: "NVARCHAR2", Length: 2001}, "length of NVARCHAR2(2001) must be between 1 and 2000"},
+		{ColumnInfo{Type: "CHAR(3000)", Length: 1}, "length of CHAR(3000 CHAR) must be between 1 and 2000"},
+		{ColumnInfo{Type: "NUMBER(40)", Length: 5}, "precision of NUMBER(40) must be between 1 and 38"},
+		{ColumnInfo{Type: "FLOAT(127)", Length: 5}, "precision of FLOAT(127) must be between 1 and 126"},
+		{ColumnInfo{Type: "TIMESTAMP(10)", Length: 19}, "fractional seconds precision of TIMESTAMP(10) must be between 0 and 9"},
+	}
+	for _, tt := range errors {
+		_, err := tt.colInfo.ColumnType(metadata)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error %q but got %v", tt.expected, err)
+		}
+	}
+
+	colInfo := ColumnInfo{Type: "VARCHAR2", Length: 5000}
+	if _, err := colInfo.ColumnType(Metadata{MaxStringSize: ExtendedMaxStringSize}); err != nil {
+		t.Errorf("Expected VARCHAR2(5000) to fit MAX_STRING_SIZE=EXTENDED but got %v", err)
+	}
+}
@@ -0,0 +1,453 @@
+package inference
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
+)
+
+func init() {
+	Register(KindBoolean, 10, func() Detector { return &booleanDetector{numeric: true} })
+	Register(KindCode, 15, func() Detector { return &codeDetector{} })
+	Register(KindInteger, 20, func() Detector { return newNumberDetector(true) })
+	Register(KindDecimal, 30, func() Detector { return newNumberDetector(false) })
+	Register(KindDate, 40, newDateDetector)
+	Register(KindTimestamp, 50, newTimestampDetector)
+	Register(KindText, 100, func() Detector { return textDetector{} })
+}
+
+var booleanValues = map[string]bool{
+	"0": true, "1": true,
+	"y": true, "n": true,
+	"yes": true, "no": true,
+	"t": true, "f": true,
+	"true": true, "false": true,
+	"так": true, "ні": true,
+	"да": true, "нет": true,
+}
+
+// booleanPairs maps each boolean value to the opposite one.
+var booleanPairs = map[string]string{
+	"0": "1", "1": "0",
+	"y": "n", "n": "y",
+	"yes": "no", "no": "yes",
+	"t": "f", "f": "t",
+	"true": "false", "false": "true",
+	"так": "ні", "ні": "так",
+	"да": "нет", "нет": "да",
+}
+
+// booleanDomain adds the opposite of each value to the domain of a boolean
+// column, written in the same case, so a column that is all Y still allows
+// N.
+func booleanDomain(values []string) []string {
+	domain := slices.Clone(values)
+	for _, value := range values {
+		opposite := []rune(booleanPairs[strings.ToLower(value)])
+		switch runes := []rune(value); {
+		case value == strings.ToUpper(value):
+			opposite = []rune(strings.ToUpper(string(opposite)))
+		case unicode.IsUpper(runes[0]):
+			opposite[0] = unicode.ToUpper(opposite[0])
+		}
+		if !slices.Contains(domain, string(opposite)) {
+			domain = append(domain, string(opposite))
+		}
+	}
+	slices.Sort(domain)
+	return domain
+}
+
+type booleanDetector struct {
+	numeric bool
+}
+
+func (d *booleanDetector) Accept(value string) bool {
+	if !booleanValues[strings.ToLower(value)] {
+		return false
+	}
+	if value != "0" && value != "1" {
+		d.numeric = false
+	}
+	return true
+}
+
+func (d *booleanDetector) Resolve(result *Result) bool {
+	result.Type = "VARCHAR2"
+	if d.numeric {
+		result.Type = "NUMBER"
+		result.Precision = 1
+	}
+	return true
+}
+
+func (d *booleanDetector) Merge(other Detector) {
+	d.numeric = d.numeric && other.(*booleanDetector).numeric
+}
+
+// minCodeWidth is the shortest fixed width at which digit strings are taken
+// for codes rather than numbers. Shorter widths are too often small counts
+// or years.
+const minCodeWidth = 5
+
+// codeDetector recognises identifiers made of digits, such as EDRPOU and IPN
+// tax codes, postal indexes and phone numbers. Loading them as NUMBER would
+// strip leading zeros, so they stay text.
+type codeDetector struct {
+	count      int
+	width      int
+	fixedWidth bool
+	evidence   string
+}
+
+func (d *codeDetector) Accept(value string) bool {
+	digits := strings.TrimPrefix(value, "+")
+	if digits == "" || !isDigits(digits) {
+		return false
+	}
+
+	if d.evidence == "" {
+		switch {
+		case digits != value:
+			d.evidence = fmt.Sprintf("value %q starts with +", value)
+		case len(value) > 1 && value[0] == '0':
+			d.evidence = fmt.Sprintf("value %q has a leading zero", value)
+		}
+	}
+
+	if d.count == 0 {
+		d.width, d.fixedWidth = len(value), true
+	} else if len(value) != d.width {
+		d.fixedWidth = false
+	}
+	d.count++
+	return true
+}
+
+func (d *codeDetector) Resolve(result *Result) bool {
+	switch {
+	case d.evidence != "":
+		result.Reason = d.evidence + ", kept as text"
+	case d.fixedWidth && d.count > 1 && d.width >= minCodeWidth:
+		result.Reason = fmt.Sprintf("all values are %d-digit codes, kept as text", d.width)
+	default:
+		return false
+	}
+	result.Type = "VARCHAR2"
+	return true
+}
+
+func (d *codeDetector) Merge(other Detector) {
+	o := other.(*codeDetector)
+	switch {
+	case d.count == 0:
+		d.width, d.fixedWidth = o.width, o.fixedWidth
+	case o.count > 0:
+		d.fixedWidth = d.fixedWidth && o.fixedWidth && d.width == o.width
+	}
+	d.count += o.count
+
+	// Keep the evidence independent of how rows were split between workers.
+	if d.evidence == "" || o.evidence != "" && o.evidence < d.evidence {
+		d.evidence = o.evidence
+	}
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// maxPrecision is the largest precision Oracle accepts in NUMBER(p,s). Wider
+// values are declared as plain NUMBER.
+const maxPrecision = 38
+
+// numberDetector tracks the widest integer and fractional parts seen so that
+// the column can be declared as NUMBER(p,s). Values are parsed in every
+// locale that has read all previous values of the column.
+type numberDetector struct {
+	integer bool
+	locales []localeState
+}
+
+type localeState struct {
+	locale     util.NumberLocale
+	intDigits  int
+	fracDigits int
+	decimal    bool
+	groups     string
+	// dotGroups is set once a value could not be read with '.' as the
+	// decimal separator, so a '.' between groups is certain.
+	dotGroups bool
+	min, max  numericValue
+}
+
+type numericValue struct {
+	text  string
+	value float64
+}
+
+func newNumberDetector(integer bool) Detector {
+	detector := &numberDetector{integer: integer}
+	for _, locale := range util.NumberLocales {
+		detector.locales = append(detector.locales, localeState{locale: locale})
+	}
+	return detector
+}
+
+func (d *numberDetector) Accept(value string) bool {
+	remaining := d.locales[:0]
+	for _, state := range d.locales {
+		num, ok := state.locale.Parse(value)
+		if !ok || d.integer && num.FracDigits > 0 {
+			continue
+		}
+		state.intDigits = max(state.intDigits, num.IntDigits)
+		state.fracDigits = max(state.fracDigits, num.FracDigits)
+		state.decimal = state.decimal || num.Decimal != 0
+		state.groups = mergeGroups(state.groups, num.Groups)
+		if strings.ContainsRune(num.Groups, '.') {
+			if _, ok := util.ParseNumber(value); !ok {
+				state.dotGroups = true
+			}
+		}
+		if f, err := state.locale.Float(value); err == nil {
+			state.extend(numericValue{text: value, value: f})
+		}
+		remaining = append(remaining, state)
+	}
+	d.locales = remaining
+	return len(d.locales) > 0
+}
+
+func (s *localeState) extend(v numericValue) {
+	if s.min.text == "" || v.value < s.min.value {
+		s.min = v
+	}
+	if s.max.text == "" || v.value > s.max.value {
+		s.max = v
+	}
+}
+
+func (d *numberDetector) Resolve(result *Result) bool {
+	state := d.locales[0]
+	// When every value reads both ways, 12.500 is more likely a decimal than
+	// twelve thousand five hundred.
+	if strings.ContainsRune(state.groups, '.') && !state.dotGroups {
+		if len(d.locales) == 1 {
+			return false
+		}
+		state = d.locales[1]
+	}
+	result.Type = "NUMBER"
+	result.Min, result.Max = state.min.text, state.max.text
+	if precision := max(state.intDigits+state.fracDigits, 1); precision <= maxPrecision {
+		result.Precision = precision
+		result.Scale = state.fracDigits
+	}
+
+	// Plain numbers with a decimal point load as they are; anything else has
+	// to be converted explicitly.
+	if state.groups != "" || state.decimal && state.locale.Decimal != '.' {
+		groups := []rune(state.groups)
+		slices.Sort(groups)
+		result.Locale = &util.NumberLocale{Decimal: state.locale.Decimal, Groups: string(groups)}
+	}
+	return true
+}
+
+func (d *numberDetector) Merge(other Detector) {
+	o := other.(*numberDetector)
+	remaining := d.locales[:0]
+	for _, state := range d.locales {
+		for _, otherState := range o.locales {
+			if otherState.locale.Decimal != state.locale.Decimal {
+				continue
+			}
+			state.intDigits = max(state.intDigits, otherState.intDigits)
+			state.fracDigits = max(state.fracDigits, otherState.fracDigits)
+			state.decimal = state.decimal || otherState.decimal
+			state.groups = mergeGroups(state.groups, otherState.groups)
+			state.dotGroups = state.dotGroups || otherState.dotGroups
+			if otherState.min.text != "" {
+				state.extend(otherState.min)
+				state.extend(otherState.max)
+			}
+			remaining = append(remaining, state)
+			break
+		}
+	}
+	d.locales = remaining
+}
+
+// mergeGroups adds the thousands separators in seen that groups lacks.
+func mergeGroups(groups, seen string) string {
+	for _, group := range seen {
+		if !strings.ContainsRune(groups, group) {
+			groups += string(group)
+		}
+	}
+	return groups
+}
+
+// dateLayout pairs a Go reference layout with the equivalent Oracle format
+// mask.
+type dateLayout struct {
+	layout string
+	mask   string
+}
+
+func (l dateLayout) join(other dateLayout) dateLayout {
+	return dateLayout{layout: l.layout + " " + other.layout, mask: l.mask + " " + other.mask}
+}
+
+// Day-first layouts come before month-first ones so that ambiguous values
+// such as 01/02/2023 are read the way our source files write them.
+var dateLayouts = []dateLayout{
+	{"2.1.2006", "DD.MM.YYYY"},
+	{"2006-1-2", "YYYY-MM-DD"},
+	{"2/1/2006", "DD/MM/YYYY"},
+	{"1/2/2006", "MM/DD/YYYY"},
+	{"2-1-2006", "DD-MM-YYYY"},
+	{"2006/1/2", "YYYY/MM/DD"},
+}
+
+var timeLayouts = []dateLayout{
+	{"15:04:05", "HH24:MI:SS"},
+	{"15:04", "HH24:MI"},
+	{"15:04:05 -07:00", "HH24:MI:SS TZH:TZM"},
+}
+
+var (
+	fractionPattern      = regexp.MustCompile(`:\d\d\.\d+`)
+	commaFractionPattern = regexp.MustCompile(`:\d\d,\d+`)
+)
+
+type layoutCandidate struct {
+	full dateLayout
+	// date is the date-only part of a timestamp layout. Oracle accepts values
+	// that stop before the time elements of a mask, so a timestamp column may
+	// mix both.
+	date     string
+	min, max datedValue
+}
+
+type datedValue struct {
+	text string
+	time time.Time
+}
+
+func (c *layoutCandidate) extend(v datedValue) {
+	if c.min.text == "" || v.time.Before(c.min.time) {
+		c.min = v
+	}
+	if c.max.text == "" || v.time.After(c.max.time) {
+		c.max = v
+	}
+}
+
+// layoutDetector keeps the layouts that parsed every value seen so far.
+type layoutDetector struct {
+	candidates []layoutCandidate
+	fraction   bool
+}
+
+func newDateDetector() Detector {
+	detector := &layoutDetector{}
+	for _, layout := range dateLayouts {
+		detector.candidates = append(detector.candidates, layoutCandidate{full: layout})
+	}
+	return detector
+}
+
+func newTimestampDetector() Detector {
+	detector := &layoutDetector{}
+	for _, date := range dateLayouts {
+		for _, clock := range timeLayouts {
+			detector.candidates = append(detector.candidates, layoutCandidate{full: date.join(clock), date: date.layout})
+		}
+	}
+	return detector
+}
+
+func (d *layoutDetector) Accept(value string) bool {
+	if commaFractionPattern.MatchString(value) {
+		return false
+	}
+	if fractionPattern.MatchString(value) {
+		d.fraction = true
+	}
+
+	remaining := d.candidates[:0]
+	for _, cand := range d.candidates {
+		t, err := time.Parse(cand.full.layout, value)
+		if err != nil && cand.date != "" {
+			t, err = time.Parse(cand.date, value)
+		}
+		if err == nil {
+			cand.extend(datedValue{text: value, time: t})
+			remaining = append(remaining, cand)
+		}
+	}
+	d.candidates = remaining
+	return len(d.candidates) > 0
+}
+
+func (d *layoutDetector) Resolve(result *Result) bool {
+	cand := d.candidates[0]
+	result.Format = cand.full.mask
+	result.Min, result.Max = cand.min.text, cand.max.text
+
+	switch {
+	case cand.date == "":
+		result.Type = "DATE"
+	case strings.HasSuffix(cand.full.mask, "TZH:TZM"):
+		result.Type = "TIMESTAMP WITH TIME ZONE"
+	default:
+		result.Type = "TIMESTAMP"
+	}
+
+	if d.fraction {
+		result.Format = strings.Replace(result.Format, ":SS", ":SS.FF", 1)
+	}
+	return true
+}
+
+func (d *layoutDetector) Merge(other Detector) {
+	o := other.(*layoutDetector)
+	remaining := d.candidates[:0]
+	for _, cand := range d.candidates {
+		for _, otherCand := range o.candidates {
+			if otherCand.full == cand.full {
+				if otherCand.min.text != "" {
+					cand.extend(otherCand.min)
+					cand.extend(otherCand.max)
+				}
+				remaining = append(remaining, cand)
+				break
+			}
+		}
+	}
+	d.candidates = remaining
+	d.fraction = d.fraction || o.fraction
+}
+
+type textDetector struct{}
+
+func (textDetector) Accept(string) bool { return true }
+
+func (textDetector) Resolve(result *Result) bool {
+	result.Type = "VARCHAR2"
+	return true
+}
+
+func (textDetector) Merge(Detector) {}
@@ -0,0 +1,320 @@
+// Package inference guesses Oracle column types from the values of a
+// delimited file.
+package inference
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
+)
+
+// Kinds of values recognised by the built-in detectors.
+const (
+	KindBoolean   = "boolean"
+	KindCode      = "code"
+	KindInteger   = "integer"
+	KindDecimal   = "decimal"
+	KindDate      = "date"
+	KindTimestamp = "timestamp"
+	KindText      = "text"
+)
+
+// MaxDomainSize is the largest number of distinct values a column can have to
+// be treated as an enumeration.
+const MaxDomainSize = 10
+
+// Number of sample values kept per column and the longest sample kept in
+// full.
+const (
+	sampleCount  = 5
+	sampleLength = 100
+)
+
+// Result is the type inferred for a single column.
+type Result struct {
+	Kind   string
+	Type   string
+	Length int
+	// Bytes is the length of the longest value in UTF-8, where Cyrillic
+	// letters take two bytes.
+	Bytes int
+	// Precision and Scale describe NUMBER columns. A zero precision means an
+	// unconstrained NUMBER.
+	Precision int
+	Scale     int
+	// Locale is set for NUMBER columns written with thousands separators or
+	// a decimal comma.
+	Locale *util.NumberLocale
+	// Format is the Oracle format mask of DATE and TIMESTAMP columns.
+	Format string
+	// Reason explains a type that is not obvious from the values, such as
+	// digits kept as text.
+	Reason string
+	// Values counts the non-empty values of the column and Nulls the empty
+	// ones.
+	Values int
+	Nulls  int
+	// Domain lists the distinct values of boolean columns and of text columns
+	// that repeat a handful of codes.
+	Domain []string
+	// Min and Max are the smallest and largest values, compared as numbers
+	// or dates for those types and as text otherwise.
+	Min, Max string
+	// Distinct estimates the number of distinct values.
+	Distinct uint64
+	// Samples are a few values of the column. The same values are picked
+	// however the rows are split between workers.
+	Samples []string
+	// Profile is only collected when Options.Profile is set.
+	Profile *Profile
+}
+
+// Detector decides whether every value of a column fits one type. A new
+// detector is created for each column and fed the column's values in turn.
+type Detector interface {
+	// Accept reports whether value fits the detector's type. A detector that
+	// rejects a value is dropped for the rest of the column.
+	Accept(value string) bool
+	// Resolve fills in the type-specific part of the result once all values
+	// have been seen. Detectors that order values also set Min and Max. It
+	// reports false if the detector turns out not to apply after all,
+	// leaving the column to the next detector.
+	Resolve(result *Result) bool
+	// Merge folds in a detector of the same kind that saw other values of
+	// the column.
+	Merge(other Detector)
+}
+
+type registration struct {
+	kind     string
+	priority int
+	factory  func() Detector
+}
+
+var registry []registration
+
+// Register adds a detector to the registry. Detectors that accept fewer
+// values get a lower priority; when several detectors accept every value of a
+// column, the one with the lowest priority wins.
+func Register(kind string, priority int, factory func() Detector) {
+	registry = append(registry, registration{kind: kind, priority: priority, factory: factory})
+	sort.SliceStable(registry, func(i, j int) bool {
+		return registry[i].priority < registry[j].priority
+	})
+}
+
+type candidate struct {
+	kind     string
+	detector Detector
+}
+
+// Column accumulates the values of one column and votes on its type.
+//
+// Every value is offered to each detector still in the running. A detector
+// that accepts all of them is compatible with the widest value seen, so the
+// most specific compatible detector is the type of the column.
+type Column struct {
+	candidates []candidate
+	length     int
+	bytes      int
+	values     int
+	nulls      int
+	// distinct is dropped once the column has more than MaxDomainSize
+	// distinct values.
+	distinct map[string]struct{}
+	min, max string
+	hll      hyperLogLog
+	// samples keeps the values with the smallest hashes.
+	samples  []sample
+	profiler *profiler
+}
+
+type sample struct {
+	hash  uint64
+	value string
+}
+
+// NewColumn returns a column accumulator with a fresh detector from every
+// registered kind.
+func NewColumn() *Column {
+	col := &Column{length: 1, bytes: 1, distinct: make(map[string]struct{})} // Set minimum length for oracle columns is 1
+	for _, reg := range registry {
+		col.candidates = append(col.candidates, candidate{kind: reg.kind, detector: reg.factory()})
+	}
+	return col
+}
+
+// Add offers a value to the remaining detectors. Empty and blank values are
+// loaded as NULL, so they are counted but do not vote.
+func (c *Column) Add(value string) {
+	if strings.TrimSpace(value) == "" {
+		c.nulls++
+		return
+	}
+	c.values++
+
+	if c.distinct != nil {
+		c.distinct[value] = struct{}{}
+		if len(c.distinct) > MaxDomainSize {
+			c.distinct = nil
+		}
+	}
+
+	if c.values == 1 || value < c.min {
+		c.min = value
+	}
+	if c.values == 1 || value > c.max {
+		c.max = value
+	}
+
+	hash := hashValue(value)
+	c.hll.add(hash)
+	c.addSample(sample{hash: hash, value: value})
+
+	// Lengths are counted in characters: the file is loaded as Windows-1251,
+	// where every character takes a single byte whatever its UTF-8 size.
+	length := utf8.RuneCountInString(value)
+	if length > c.length {
+		c.length = length
+	}
+	c.bytes = max(c.bytes, len(value))
+	if c.profiler != nil {
+		c.profiler.add(value, length, hash)
+	}
+
+	remaining := c.candidates[:0]
+	for _, cand := range c.candidates {
+		if cand.detector.Accept(value) {
+			remaining = append(remaining, cand)
+		}
+	}
+	c.candidates = remaining
+}
+
+// Merge folds in an accumulator of the same column that saw a different set
+// of rows. Only detectors that accepted every value on both sides remain.
+func (c *Column) Merge(other *Column) {
+	c.length = max(c.length, other.length)
+	c.bytes = max(c.bytes, other.bytes)
+	if other.values > 0 && (c.values == 0 || other.min < c.min) {
+		c.min = other.min
+	}
+	if other.values > 0 && (c.values == 0 || other.max > c.max) {
+		c.max = other.max
+	}
+	c.values += other.values
+	c.nulls += other.nulls
+
+	c.hll.merge(&other.hll)
+	for _, s := range other.samples {
+		c.addSample(s)
+	}
+	if c.profiler != nil && other.profiler != nil {
+		c.profiler.merge(other.profiler)
+	}
+
+	if c.distinct != nil && other.distinct != nil {
+		for value := range other.distinct {
+			c.distinct[value] = struct{}{}
+		}
+	}
+	if c.distinct == nil || other.distinct == nil || len(c.distinct) > MaxDomainSize {
+		c.distinct = nil
+	}
+
+	remaining := c.candidates[:0]
+	for _, cand := range c.candidates {
+		for _, otherCand := range other.candidates {
+			if otherCand.kind == cand.kind {
+				cand.detector.Merge(otherCand.detector)
+				remaining = append(remaining, cand)
+				break
+			}
+		}
+	}
+	c.candidates = remaining
+}
+
+func (c *Column) addSample(s sample) {
+	i := sort.Search(len(c.samples), func(i int) bool { return c.samples[i].hash >= s.hash })
+	if i == sampleCount || i < len(c.samples) && c.samples[i].hash == s.hash {
+		return
+	}
+	if len(c.samples) < sampleCount {
+		c.samples = append(c.samples, sample{})
+	}
+	copy(c.samples[i+1:], c.samples[i:])
+	c.samples[i] = s
+}
+
+// Result returns the type of the most specific detector that accepted every
+// value. Columns no detector agreed on, or without any values, are text.
+func (c *Column) Result() Result {
+	result := c.resolve()
+
+	if result.Min == "" && result.Max == "" {
+		result.Min, result.Max = c.min, c.max
+	}
+	if c.values > 0 {
+		result.Distinct = min(max(c.hll.estimate(), 1), uint64(c.values))
+	}
+	for _, s := range c.samples {
+		value := s.value
+		if runes := []rune(value); len(runes) > sampleLength {
+			value = string(runes[:sampleLength]) + "..."
+		}
+		result.Samples = append(result.Samples, value)
+	}
+	if c.profiler != nil {
+		result.Profile = c.profiler.result()
+	}
+
+	switch result.Kind {
+	case KindBoolean:
+		if domain := c.domain(); domain != nil {
+			result.Domain = booleanDomain(domain)
+			// Leave room for the opposite values
+			for _, value := range result.Domain {
+				result.Length = max(result.Length, utf8.RuneCountInString(value))
+				result.Bytes = max(result.Bytes, len(value))
+			}
+		}
+	case KindText, KindCode:
+		// A handful of distinct values is only an enumeration if they repeat
+		if len(c.distinct) > 0 && c.values >= 2*len(c.distinct) {
+			result.Domain = c.domain()
+		}
+	}
+
+	// Single-character flags and codes are fixed width
+	if result.Domain != nil && result.Type == "VARCHAR2" && result.Length == 1 {
+		result.Type = "CHAR"
+	}
+	return result
+}
+
+func (c *Column) resolve() Result {
+	if c.values > 0 {
+		for _, cand := range c.candidates {
+			result := Result{Kind: cand.kind, Length: c.length, Bytes: c.bytes, Values: c.values, Nulls: c.nulls}
+			if cand.detector.Resolve(&result) {
+				return result
+			}
+		}
+	}
+	return Result{Kind: KindText, Type: "VARCHAR2", Length: c.length, Bytes: c.bytes, Values: c.values, Nulls: c.nulls}
+}
+
+func (c *Column) domain() []string {
+	if c.distinct == nil {
+		return nil
+	}
+	domain := make([]string, 0, len(c.distinct))
+	for value := range c.distinct {
+		domain = append(domain, value)
+	}
+	sort.Strings(domain)
+	return domain
+}
@@ -0,0 +1,255 @@
+package inference
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func inferColumn(values ...string) Result {
+	col := NewColumn()
+	for _, value := range values {
+		col.Add(value)
+	}
+	return col.Result()
+}
+
+func TestColumn_Kinds(t *testing.T) {
+	tests := []struct {
+		values   []string
+		kind     string
+		dataType string
+	}{
+		{[]string{"0", "1", "1"}, KindBoolean, "NUMBER"},
+		{[]string{"Так", "Ні"}, KindBoolean, "VARCHAR2"},
+		{[]string{"12", "345", "-7"}, KindInteger, "NUMBER"},
+		{[]string{"12", "3.5"}, KindDecimal, "NUMBER"},
+		{[]string{"31.12.2023", "01.01.2024"}, KindDate, "DATE"},
+		{[]string{"2023-12-31 14:05:00"}, KindTimestamp, "TIMESTAMP"},
+		{[]string{"12", "abc"}, KindText, "VARCHAR2"},
+		{[]string{"31.12.2023", "2023-12-31"}, KindText, "VARCHAR2"},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Kind != tt.kind || result.Type != tt.dataType {
+			t.Errorf("Expected %s/%s for %v but got %s/%s", tt.kind, tt.dataType, tt.values, result.Kind, result.Type)
+		}
+	}
+}
+
+func TestColumn_Length(t *testing.T) {
+	result := inferColumn("a", "abcd", "ab")
+	if result.Length != 4 {
+		t.Errorf("Expected length 4 but got %d", result.Length)
+	}
+
+	result = inferColumn()
+	if result.Length != 1 {
+		t.Errorf("Expected minimum length 1 but got %d", result.Length)
+	}
+}
+
+func TestColumn_DateFormats(t *testing.T) {
+	tests := []struct {
+		values   []string
+		dataType string
+		format   string
+	}{
+		{[]string{"31.12.2023", "1.01.2024"}, "DATE", "DD.MM.YYYY"},
+		{[]string{"31/12/2023"}, "DATE", "DD/MM/YYYY"},
+		{[]string{"12/31/2023", "01/02/2024"}, "DATE", "MM/DD/YYYY"},
+		{[]string{"2023-12-31"}, "DATE", "YYYY-MM-DD"},
+		{[]string{"2023-12-31 14:05:00", "2024-01-01"}, "TIMESTAMP", "YYYY-MM-DD HH24:MI:SS"},
+		{[]string{"31.12.2023 14:05"}, "TIMESTAMP", "DD.MM.YYYY HH24:MI"},
+		{[]string{"2023-12-31 14:05:00.123456"}, "TIMESTAMP", "YYYY-MM-DD HH24:MI:SS.FF"},
+		{[]string{"2023-12-31 14:05:00 +02:00"}, "TIMESTAMP WITH TIME ZONE", "YYYY-MM-DD HH24:MI:SS TZH:TZM"},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Type != tt.dataType || result.Format != tt.format {
+			t.Errorf("Expected %s %q for %v but got %s %q", tt.dataType, tt.format, tt.values, result.Type, result.Format)
+		}
+	}
+}
+
+func TestColumn_NumberPrecision(t *testing.T) {
+	tests := []struct {
+		values    []string
+		kind      string
+		precision int
+		scale     int
+	}{
+		{[]string{"12", "-345"}, KindInteger, 3, 0},
+		{[]string{"-12.50", "1234.5"}, KindDecimal, 6, 2},
+		{[]string{"-12,50", "1 234,5"}, KindDecimal, 6, 2},
+		{[]string{"1e3"}, KindInteger, 4, 0},
+		{[]string{"123456789012345"}, KindInteger, 15, 0},
+		{[]string{"1e50"}, KindInteger, 0, 0},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Kind != tt.kind || result.Precision != tt.precision || result.Scale != tt.scale {
+			t.Errorf("Expected %s(%d,%d) for %v but got %s(%d,%d)", tt.kind, tt.precision, tt.scale, tt.values, result.Kind, result.Precision, result.Scale)
+		}
+	}
+}
+
+func TestColumn_NumericLocale(t *testing.T) {
+	tests := []struct {
+		values  []string
+		decimal rune
+		groups  string
+	}{
+		{[]string{"1 234 567,89", "12,5"}, ',', " "},
+		{[]string{"1\u00a0234,5", "1 000"}, ',', " \u00a0"},
+		{[]string{"1,234,567.89", "0.5"}, '.', ","},
+		{[]string{"12,5"}, ',', ""},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Type != "NUMBER" || result.Locale == nil {
+			t.Errorf("Expected a localised NUMBER for %v but got %s", tt.values, result.Type)
+			continue
+		}
+		if result.Locale.Decimal != tt.decimal || result.Locale.Groups != tt.groups {
+			t.Errorf("Expected %c/%q for %v but got %c/%q", tt.decimal, tt.groups, tt.values, result.Locale.Decimal, result.Locale.Groups)
+		}
+	}
+
+	if result := inferColumn("12.5", "3"); result.Locale != nil {
+		t.Errorf("Expected plain decimals to need no locale but got %+v", *result.Locale)
+	}
+	if result := inferColumn("12.5", "12,5"); result.Kind != KindText {
+		t.Errorf("Expected mixed decimal separators to be text but got %s", result.Kind)
+	}
+
+	// A dot followed by three digits is a decimal point unless a value
+	// rules it out
+	for _, values := range [][]string{{"0.125", "0.250", "0.375"}, {"12.500", "13.250", "14.000"}} {
+		result := inferColumn(values...)
+		if result.Kind != KindDecimal || result.Locale != nil || result.Scale != 3 {
+			t.Errorf("Expected decimals with scale 3 and no locale for %v but got %s with scale %d", values, result.Kind, result.Scale)
+		}
+	}
+	result := inferColumn("12.500", "1.234.567")
+	if result.Kind != KindInteger || result.Locale == nil || result.Locale.Groups != "." {
+		t.Errorf("Expected integers grouped with dots for [12.500 1.234.567] but got %s", result.Kind)
+	}
+}
+
+func TestColumn_Codes(t *testing.T) {
+	tests := []struct {
+		values []string
+		kind   string
+	}{
+		{[]string{"0012345", "1234567"}, KindCode},
+		{[]string{"+380501234567"}, KindCode},
+		{[]string{"12345678", "87654321"}, KindCode},
+		{[]string{"01001", "79000"}, KindCode},
+		{[]string{"1234", "5678"}, KindInteger},
+		{[]string{"12345678", "123"}, KindInteger},
+		{[]string{"0", "10", "200"}, KindInteger},
+		{[]string{"0.5", "10"}, KindDecimal},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Kind != tt.kind {
+			t.Errorf("Expected %s for %v but got %s", tt.kind, tt.values, result.Kind)
+		}
+		if tt.kind == KindCode && (result.Type != "VARCHAR2" || result.Reason == "") {
+			t.Errorf("Expected a VARCHAR2 with a reason for %v but got %s %q", tt.values, result.Type, result.Reason)
+		}
+	}
+}
+
+func TestColumn_LengthInCharacters(t *testing.T) {
+	result := inferColumn("Тест", "Привіт")
+	if result.Length != 6 || result.Bytes != 12 {
+		t.Errorf("Expected length 6 of 12 bytes but got %d of %d", result.Length, result.Bytes)
+	}
+}
+
+func TestColumn_Nulls(t *testing.T) {
+	result := inferColumn("12", "", "  ", "345")
+	if result.Kind != KindInteger || result.Nulls != 2 || result.Values != 2 {
+		t.Errorf("Expected an integer with 2 nulls but got %s with %d nulls and %d values", result.Kind, result.Nulls, result.Values)
+	}
+
+	result = inferColumn("", "")
+	if result.Kind != KindText || result.Type != "VARCHAR2" || result.Nulls != 2 {
+		t.Errorf("Expected an empty column to be text but got %s %s", result.Kind, result.Type)
+	}
+}
+
+func TestColumn_Domain(t *testing.T) {
+	tests := []struct {
+		values   []string
+		kind     string
+		dataType string
+		domain   []string
+	}{
+		{[]string{"Y", "N", "Y"}, KindBoolean, "CHAR", []string{"N", "Y"}},
+		{[]string{"Так", "Ні", "Так"}, KindBoolean, "VARCHAR2", []string{"Ні", "Так"}},
+		{[]string{"0", "1"}, KindBoolean, "NUMBER", []string{"0", "1"}},
+		{[]string{"Y", "Y"}, KindBoolean, "CHAR", []string{"N", "Y"}},
+		{[]string{"Так", "Так"}, KindBoolean, "VARCHAR2", []string{"Ні", "Так"}},
+		{[]string{"true", "true"}, KindBoolean, "VARCHAR2", []string{"false", "true"}},
+		{[]string{"A", "B", "C", "A", "B", "C"}, KindText, "CHAR", []string{"A", "B", "C"}},
+		{[]string{"new", "open", "closed", "open"}, KindText, "VARCHAR2", nil},
+		{[]string{"new", "open", "new", "open"}, KindText, "VARCHAR2", []string{"new", "open"}},
+		{[]string{"12", "13", "12", "13"}, KindInteger, "NUMBER", nil},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Kind != tt.kind || result.Type != tt.dataType || !reflect.DeepEqual(result.Domain, tt.domain) {
+			t.Errorf("Expected %s %s %v for %v but got %s %s %v", tt.kind, tt.dataType, tt.domain, tt.values, result.Kind, result.Type, result.Domain)
+		}
+	}
+
+	values := make([]string, 0, 2*(MaxDomainSize+1))
+	for i := 0; i <= MaxDomainSize; i++ {
+		values = append(values, fmt.Sprintf("code%d", i), fmt.Sprintf("code%d", i))
+	}
+	if result := inferColumn(values...); result.Domain != nil {
+		t.Errorf("Expected no domain for %d distinct values but got %v", MaxDomainSize+1, result.Domain)
+	}
+}
+
+func TestColumn_Stats(t *testing.T) {
+	tests := []struct {
+		values   []string
+		min, max string
+	}{
+		{[]string{"9", "10", "-2,5"}, "-2,5", "10"},
+		{[]string{"31.12.2023", "01.02.2024", "15.06.2023"}, "15.06.2023", "01.02.2024"},
+		{[]string{"b", "a", "", "c"}, "a", "c"},
+	}
+
+	for _, tt := range tests {
+		result := inferColumn(tt.values...)
+		if result.Min != tt.min || result.Max != tt.max {
+			t.Errorf("Expected range %s..%s for %v but got %s..%s", tt.min, tt.max, tt.values, result.Min, result.Max)
+		}
+	}
+
+	col := NewColumn()
+	for i := 0; i < 20000; i++ {
+		col.Add(fmt.Sprintf("value %d", i%5000))
+	}
+	result := col.Result()
+	if result.Distinct < 4800 || result.Distinct > 5200 {
+		t.Errorf("Expected about 5000 distinct values but got %d", result.Distinct)
+	}
+	if len(result.Samples) != sampleCount {
+		t.Errorf("Expected %d samples but got %v", sampleCount, result.Samples)
+	}
+	if result = inferColumn("a", "a", "b"); result.Distinct != 2 || len(result.Samples) != 2 {
+		t.Errorf("Expected 2 distinct values and samples but got %d and %v", result.Distinct, result.Samples)
+	}
+}
@@ -0,0 +1,190 @@
+package inference
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxKeyHashes caps the value hashes remembered for all candidate keys
+	// together, up to about 100 MB. Once it is reached, the candidates are
+	// dropped from the last, pairs before columns, rather than letting
+	// memory grow with the file.
+	MaxKeyHashes = 1 << 22
+	// keyWarmupRows are buffered before choosing the columns to pair up.
+	keyWarmupRows = 1000
+	// maxPairColumns limits pair candidates to combinations of this many
+	// columns.
+	maxPairColumns = 5
+)
+
+// keySet tracks whether the values of one column or column combination are
+// unique and never empty.
+type keySet struct {
+	columns []int
+	hashes  map[uint64]struct{}
+	alive   bool
+}
+
+func newKeySet(columns ...int) *keySet {
+	return &keySet{columns: columns, hashes: make(map[uint64]struct{}), alive: true}
+}
+
+func (k *keySet) add(row []string) {
+	if !k.alive {
+		return
+	}
+
+	h := fnv.New64a()
+	for i, col := range k.columns {
+		if strings.TrimSpace(row[col]) == "" {
+			k.drop()
+			return
+		}
+		if i > 0 {
+			h.Write([]byte{0x1f})
+		}
+		h.Write([]byte(row[col]))
+	}
+
+	sum := h.Sum64()
+	if _, ok := k.hashes[sum]; ok {
+		k.drop()
+		return
+	}
+	k.hashes[sum] = struct{}{}
+}
+
+func (k *keySet) drop() {
+	k.alive, k.hashes = false, nil
+}
+
+// keyTracker finds columns, and optionally pairs of columns, that are unique
+// and never empty. Pairs are only tried between columns that are complete but
+// not unique on their own; they are chosen from the first rows, which are
+// kept until then and replayed.
+type keyTracker struct {
+	singles []*keySet
+	pairs   []*keySet
+	// live are the sets that are still candidates, singles first.
+	live []*keySet
+	// maxHashes is MaxKeyHashes, lowered in tests.
+	maxHashes int
+	withPairs bool
+	warmup    [][]string
+	rows      int
+}
+
+func newKeyTracker(columns int, withPairs bool) *keyTracker {
+	tracker := &keyTracker{withPairs: withPairs, maxHashes: MaxKeyHashes}
+	for i := 0; i < columns; i++ {
+		tracker.singles = append(tracker.singles, newKeySet(i))
+	}
+	tracker.live = append(tracker.live, tracker.singles...)
+	return tracker
+}
+
+func (t *keyTracker) add(row []string) {
+	t.rows++
+	if t.withPairs && t.rows <= keyWarmupRows {
+		// Pairs are started after the warmup rows and replay them
+		t.warmup = append(t.warmup, row)
+		for _, single := range t.singles {
+			single.add(row)
+		}
+		if t.rows == keyWarmupRows {
+			t.startPairs()
+		}
+		t.prune()
+		return
+	}
+	for _, set := range t.live {
+		set.add(row)
+	}
+	t.prune()
+}
+
+// prune forgets the sets that are no longer candidates and drops the last
+// ones while their hashes exceed MaxKeyHashes.
+func (t *keyTracker) prune() {
+	live, hashes := t.live[:0], 0
+	for _, set := range t.live {
+		if set.alive {
+			live = append(live, set)
+			hashes += len(set.hashes)
+		}
+	}
+	for hashes > t.maxHashes {
+		last := live[len(live)-1]
+		hashes -= len(last.hashes)
+		last.drop()
+		live = live[:len(live)-1]
+	}
+	t.live = live
+}
+
+// startPairs picks the complete, non-unique columns with the most distinct
+// values in the warmup rows and replays those rows into their pairs.
+func (t *keyTracker) startPairs() {
+	var columns []int
+	distinct := make(map[int]int)
+	for i := range t.singles {
+		seen := make(map[string]struct{})
+		complete := true
+		for _, row := range t.warmup {
+			if strings.TrimSpace(row[i]) == "" {
+				complete = false
+				break
+			}
+			seen[row[i]] = struct{}{}
+		}
+		if complete && len(seen) > 1 && len(seen) < len(t.warmup) {
+			columns = append(columns, i)
+			distinct[i] = len(seen)
+		}
+	}
+
+	sort.SliceStable(columns, func(a, b int) bool { return distinct[columns[a]] > distinct[columns[b]] })
+	if len(columns) > maxPairColumns {
+		columns = columns[:maxPairColumns]
+	}
+	sort.Ints(columns)
+
+	for a := range columns {
+		for b := a + 1; b < len(columns); b++ {
+			t.pairs = append(t.pairs, newKeySet(columns[a], columns[b]))
+		}
+	}
+	for _, row := range t.warmup {
+		for _, pair := range t.pairs {
+			pair.add(row)
+		}
+	}
+	t.live = append(t.live, t.pairs...)
+	t.warmup = nil
+}
+
+// candidates returns the unique columns followed by the unique pairs whose
+// columns are not unique on their own.
+func (t *keyTracker) candidates() [][]int {
+	if t.rows < 2 {
+		return nil
+	}
+	if t.withPairs && t.warmup != nil {
+		t.startPairs()
+	}
+
+	var result [][]int
+	for _, single := range t.singles {
+		if single.alive {
+			result = append(result, single.columns)
+		}
+	}
+	for _, pair := range t.pairs {
+		if pair.alive && !t.singles[pair.columns[0]].alive && !t.singles[pair.columns[1]].alive {
+			result = append(result, pair.columns)
+		}
+	}
+	return result
+}
@@ -0,0 +1,189 @@
+package inference
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// topValuesCapacity is the number of counters kept for frequent values. Counts
+// are exact while a column has no more distinct values than that.
+const topValuesCapacity = 64
+
+var datePattern = regexp.MustCompile(`^\d{1,4}[./-]\d{1,2}[./-]\d{1,4}`)
+
+// Profile is the data-quality detail of a column, collected when
+// Options.Profile is set.
+type Profile struct {
+	// Lengths counts the values of each length in characters.
+	Lengths map[int]int
+	// TopValues are the most frequent values, most frequent first. Counts
+	// are lower bounds once the column has more distinct values than are
+	// tracked.
+	TopValues []ValueCount
+	// Numeric and Dated count the values that look like numbers or dates,
+	// whatever the type of the column.
+	Numeric, Dated int
+	// Anomalies found in the values, by kind.
+	Whitespace, Encoding, Other Anomaly
+}
+
+// ValueCount is a value and the number of times it occurs.
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// Anomaly counts values with a problem and keeps one of them as an example.
+type Anomaly struct {
+	Count   int
+	Example string
+	hash    uint64
+}
+
+func (a *Anomaly) add(value string, hash uint64) {
+	if a.Count == 0 || hash < a.hash {
+		a.Example, a.hash = value, hash
+	}
+	a.Count++
+}
+
+func (a *Anomaly) merge(other Anomaly) {
+	if other.Count > 0 && (a.Count == 0 || other.hash < a.hash) {
+		a.Example, a.hash = other.Example, other.hash
+	}
+	a.Count += other.Count
+}
+
+// profiler collects a Profile. Frequent values are found with the
+// Misra-Gries algorithm, which keeps memory bounded and can be merged.
+type profiler struct {
+	profile  Profile
+	counters map[string]int
+}
+
+func newProfiler() *profiler {
+	return &profiler{profile: Profile{Lengths: make(map[int]int)}, counters: make(map[string]int)}
+}
+
+func (p *profiler) add(value string, length int, hash uint64) {
+	p.profile.Lengths[length]++
+	p.count(value, 1)
+
+	switch {
+	case looksNumeric(value):
+		p.profile.Numeric++
+	case datePattern.MatchString(value):
+		p.profile.Dated++
+	default:
+		p.profile.Other.add(value, hash)
+	}
+
+	if strings.TrimSpace(value) != value {
+		p.profile.Whitespace.add(value, hash)
+	}
+	if hasEncodingProblem(value) {
+		p.profile.Encoding.add(value, hash)
+	}
+}
+
+func (p *profiler) count(value string, n int) {
+	if _, ok := p.counters[value]; ok || len(p.counters) < topValuesCapacity {
+		p.counters[value] += n
+		return
+	}
+
+	// No room for the value: take its count off every counter instead
+	decrement := n
+	for _, count := range p.counters {
+		decrement = min(decrement, count)
+	}
+	for v, count := range p.counters {
+		if count -= decrement; count == 0 {
+			delete(p.counters, v)
+		} else {
+			p.counters[v] = count
+		}
+	}
+	if n > decrement {
+		p.count(value, n-decrement)
+	}
+}
+
+func (p *profiler) merge(other *profiler) {
+	for length, count := range other.profile.Lengths {
+		p.profile.Lengths[length] += count
+	}
+	for value, count := range other.counters {
+		p.count(value, count)
+	}
+	p.profile.Numeric += other.profile.Numeric
+	p.profile.Dated += other.profile.Dated
+	p.profile.Whitespace.merge(other.profile.Whitespace)
+	p.profile.Encoding.merge(other.profile.Encoding)
+	p.profile.Other.merge(other.profile.Other)
+}
+
+func (p *profiler) result() *Profile {
+	profile := p.profile
+	profile.TopValues = make([]ValueCount, 0, len(p.counters))
+	for value, count := range p.counters {
+		profile.TopValues = append(profile.TopValues, ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(profile.TopValues, func(i, j int) bool {
+		a, b := profile.TopValues[i], profile.TopValues[j]
+		return a.Count > b.Count || a.Count == b.Count && a.Value < b.Value
+	})
+	return &profile
+}
+
+func looksNumeric(value string) bool {
+	value = strings.TrimSpace(value)
+	for _, locale := range util.NumberLocales {
+		if _, ok := locale.Parse(value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasEncodingProblem reports replacement characters, control characters and
+// UTF-8 text that was decoded as Windows-1251.
+func hasEncodingProblem(value string) bool {
+	for _, r := range value {
+		if r == unicode.ReplacementChar || unicode.IsControl(r) && r != '\t' {
+			return true
+		}
+	}
+	return isMojibake(value)
+}
+
+// isMojibake reports UTF-8 Cyrillic text that was decoded as Windows-1251,
+// where each letter shows as Р or С and another character. Encoded back to
+// Windows-1251, such text is valid UTF-8 with letters of the modern Cyrillic
+// alphabets and no other non-Latin letters; ordinary text with quotes or
+// dashes after Р or С is not.
+func isMojibake(value string) bool {
+	if !strings.ContainsAny(value, "РС") {
+		return false
+	}
+	encoded, err := charmap.Windows1251.NewEncoder().String(value)
+	if err != nil || !utf8.ValidString(encoded) {
+		return false
+	}
+	cyrillic := false
+	for _, r := range encoded {
+		switch {
+		case r >= 'Ѐ' && r <= 'џ', r == 'Ґ', r == 'ґ':
+			cyrillic = true
+		case r >= utf8.RuneSelf && unicode.IsLetter(r):
+			return false
+		}
+	}
+	return cyrillic
+}
@@ -0,0 +1,217 @@
+package inference
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+)
+
+// batchSize is the number of rows handed to a worker at a time.
+const batchSize = 1024
+
+// Sampling strategies for Options.Sample.
+const (
+	// SampleHead infers from the first SampleSize rows and stops reading.
+	SampleHead = "head"
+	// SampleReservoir infers from SampleSize rows picked uniformly at random
+	// from the whole file.
+	SampleReservoir = "reservoir"
+	// SampleStride infers from every SampleSize-th row.
+	SampleStride = "stride"
+)
+
+// Options tunes how a file is scanned.
+type Options struct {
+	// Workers is the number of goroutines rows are spread across. With one
+	// worker or fewer the file is scanned on the calling goroutine.
+	Workers int
+	// Sample selects a sampling strategy. Every row is used when it is empty.
+	Sample     string
+	SampleSize int
+	// KeyPairs also looks for pairs of columns that are unique together.
+	KeyPairs bool
+	// Profile collects the data-quality details returned in Result.Profile.
+	Profile bool
+}
+
+// Table is the result of scanning a delimited file. Only per-column
+// accumulators are kept, so memory does not grow with the size of the file.
+type Table struct {
+	Headers []string
+	Columns []*Column
+	// Rows is the number of data rows read and Sampled the number of them
+	// the columns were inferred from.
+	Rows    int
+	Sampled int
+	// Keys lists the columns, or pairs of columns, whose values are unique
+	// and never empty. Keys are only looked for when every row is scanned.
+	Keys [][]int
+}
+
+func newTable(headers []string, opts Options) *Table {
+	table := &Table{Headers: headers, Columns: make([]*Column, len(headers))}
+	for i := range table.Columns {
+		table.Columns[i] = NewColumn()
+		if opts.Profile {
+			table.Columns[i].profiler = newProfiler()
+		}
+	}
+	return table
+}
+
+func (t *Table) add(row []string) {
+	for i, value := range row {
+		t.Columns[i].Add(value)
+	}
+}
+
+func (t *Table) merge(other *Table) {
+	for i, col := range t.Columns {
+		col.Merge(other.Columns[i])
+	}
+}
+
+// Scan reads a delimited file with a header row and infers the type of every
+// column in a single streaming pass.
+func Scan(r io.Reader, delimiter rune, opts Options) (*Table, error) {
+	switch opts.Sample {
+	case "", SampleHead, SampleReservoir, SampleStride:
+	default:
+		return nil, fmt.Errorf("unknown sampling strategy %q", opts.Sample)
+	}
+	if opts.Sample != "" && opts.SampleSize <= 0 {
+		return nil, fmt.Errorf("sample size must be positive, got %d", opts.SampleSize)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading header row: %v", err)
+	}
+
+	// Keys are tracked on the reading goroutine, as uniqueness has to be
+	// checked across all rows
+	var keys *keyTracker
+	if opts.Sample == "" {
+		keys = newKeyTracker(len(headers), opts.KeyPairs)
+	}
+
+	var table *Table
+	var rows, sampled int
+	if opts.Workers <= 1 {
+		table = newTable(headers, opts)
+		rows, sampled, err = sampleRows(reader, opts, keys, table.add)
+	} else {
+		table, rows, sampled, err = scanParallel(reader, headers, opts, keys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table.Rows, table.Sampled = rows, sampled
+	if keys != nil {
+		table.Keys = keys.candidates()
+	}
+	return table, nil
+}
+
+// sampleRows reads every row and passes the ones selected by the sampling
+// strategy to fn, and every row to keys if it is set. It returns the number
+// of rows read and selected.
+func sampleRows(reader *csv.Reader, opts Options, keys *keyTracker, fn func(row []string)) (int, int, error) {
+	var reservoir [][]string
+	// A fixed seed keeps plans reproducible for the same file.
+	random := rand.New(rand.NewSource(1))
+
+	rows, sampled := 0, 0
+	for {
+		if opts.Sample == SampleHead && rows == opts.SampleSize {
+			break
+		}
+
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		rows++
+		if keys != nil {
+			keys.add(row)
+		}
+
+		switch opts.Sample {
+		case SampleReservoir:
+			if len(reservoir) < opts.SampleSize {
+				reservoir = append(reservoir, row)
+			} else if i := random.Intn(rows); i < opts.SampleSize {
+				reservoir[i] = row
+			}
+		case SampleStride:
+			if (rows-1)%opts.SampleSize == 0 {
+				fn(row)
+				sampled++
+			}
+		default:
+			fn(row)
+			sampled++
+		}
+	}
+
+	for _, row := range reservoir {
+		fn(row)
+		sampled++
+	}
+	return rows, sampled, nil
+}
+
+// scanParallel reads rows on the calling goroutine and fans them out in
+// batches to workers that each keep their own accumulators. The partial
+// results are merged once the file has been read.
+func scanParallel(reader *csv.Reader, headers []string, opts Options, keys *keyTracker) (*Table, int, int, error) {
+	batches := make(chan [][]string, opts.Workers*2)
+	partials := make([]*Table, opts.Workers)
+
+	var wg sync.WaitGroup
+	for w := range partials {
+		partials[w] = newTable(headers, opts)
+		wg.Add(1)
+		go func(table *Table) {
+			defer wg.Done()
+			for batch := range batches {
+				for _, row := range batch {
+					table.add(row)
+				}
+			}
+		}(partials[w])
+	}
+
+	batch := make([][]string, 0, batchSize)
+	rows, sampled, err := sampleRows(reader, opts, keys, func(row []string) {
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			batches <- batch
+			batch = make([][]string, 0, batchSize)
+		}
+	})
+	if len(batch) > 0 && err == nil {
+		batches <- batch
+	}
+	close(batches)
+	wg.Wait()
+
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	table := partials[0]
+	for _, partial := range partials[1:] {
+		table.merge(partial)
+	}
+	return table, rows, sampled, nil
+}
@@ -0,0 +1,167 @@
+package inference
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScan_ParallelMatchesSequential(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id;amount;date;code;name\n")
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&sb, "%d;%d,%02d;%02d.01.2024;%08d;name %d\n", i, i*7, i%100, i%28+1, i, i)
+	}
+
+	sequential, err := Scan(strings.NewReader(sb.String()), ';', Options{})
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+	parallel, err := Scan(strings.NewReader(sb.String()), ';', Options{Workers: 4})
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+
+	if sequential.Rows != 5000 || parallel.Rows != 5000 {
+		t.Errorf("Expected 5000 rows but got %d and %d", sequential.Rows, parallel.Rows)
+	}
+	for i, header := range sequential.Headers {
+		expected, result := sequential.Columns[i].Result(), parallel.Columns[i].Result()
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("Expected %+v for %s but got %+v", expected, header, result)
+		}
+	}
+}
+
+func TestScan_ReadError(t *testing.T) {
+	data := "a;b\n1;2\n3\n"
+	for _, workers := range []int{1, 4} {
+		if _, err := Scan(strings.NewReader(data), ';', Options{Workers: workers}); err == nil {
+			t.Errorf("Expected an error for a short row with %d workers", workers)
+		}
+	}
+}
+
+func TestScan_Sampling(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("value\n")
+	for i := 1; i <= 1000; i++ {
+		if i == 1000 {
+			sb.WriteString("not a number\n")
+		} else {
+			fmt.Fprintf(&sb, "%d\n", i)
+		}
+	}
+
+	tests := []struct {
+		opts    Options
+		rows    int
+		sampled int
+	}{
+		{Options{Sample: SampleHead, SampleSize: 100}, 100, 100},
+		{Options{Sample: SampleStride, SampleSize: 10}, 1000, 100},
+		{Options{Sample: SampleReservoir, SampleSize: 50}, 1000, 50},
+		{Options{Sample: SampleReservoir, SampleSize: 50, Workers: 4}, 1000, 50},
+		{Options{Sample: SampleReservoir, SampleSize: 5000}, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		table, err := Scan(strings.NewReader(sb.String()), ';', tt.opts)
+		if err != nil {
+			t.Fatalf("Failed to scan with %+v: %v", tt.opts, err)
+		}
+		if table.Rows != tt.rows || table.Sampled != tt.sampled {
+			t.Errorf("Expected %d/%d rows with %+v but got %d/%d", tt.rows, tt.sampled, tt.opts, table.Rows, table.Sampled)
+		}
+	}
+
+	table, _ := Scan(strings.NewReader(sb.String()), ';', Options{Sample: SampleHead, SampleSize: 100})
+	if kind := table.Columns[0].Result().Kind; kind != KindInteger {
+		t.Errorf("Expected the first 100 rows to be integer but got %s", kind)
+	}
+
+	if _, err := Scan(strings.NewReader(sb.String()), ';', Options{Sample: "every"}); err == nil {
+		t.Errorf("Expected an error for an unknown sampling strategy")
+	}
+}
+
+func TestScan_Keys(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("id;doc_no;doc_date;amount;note\n")
+	for i := 0; i < 3000; i++ {
+		note := "x"
+		if i%2 == 0 {
+			note = ""
+		}
+		fmt.Fprintf(&sb, "%d;%d;%02d.01.2024;%d;%s\n", i, i/3, i%3+1, i/3%7, note)
+	}
+
+	table, err := Scan(strings.NewReader(sb.String()), ';', Options{})
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+	if !reflect.DeepEqual(table.Keys, [][]int{{0}}) {
+		t.Errorf("Expected only id as a key but got %v", table.Keys)
+	}
+
+	table, err = Scan(strings.NewReader(sb.String()), ';', Options{KeyPairs: true, Workers: 4})
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+	if !reflect.DeepEqual(table.Keys, [][]int{{0}, {1, 2}}) {
+		t.Errorf("Expected id and doc_no with doc_date as keys but got %v", table.Keys)
+	}
+
+	table, _ = Scan(strings.NewReader(sb.String()), ';', Options{Sample: SampleHead, SampleSize: 10})
+	if table.Keys != nil {
+		t.Errorf("Expected no keys from a sample but got %v", table.Keys)
+	}
+}
+
+func TestKeyTracker_MaxHashes(t *testing.T) {
+	tracker := newKeyTracker(3, false)
+	tracker.maxHashes = 250
+	for i := 0; i < 100; i++ {
+		n := strconv.Itoa(i)
+		tracker.add([]string{n, "a" + n, "b" + n})
+	}
+	if !reflect.DeepEqual(tracker.candidates(), [][]int{{0}, {1}}) {
+		t.Errorf("Expected the last column to be dropped at the limit but got %v", tracker.candidates())
+	}
+
+	// A single candidate may use the whole limit
+	tracker = newKeyTracker(2, false)
+	tracker.maxHashes = 250
+	for i := 0; i < 200; i++ {
+		tracker.add([]string{strconv.Itoa(i), "x"})
+	}
+	if !reflect.DeepEqual(tracker.candidates(), [][]int{{0}}) {
+		t.Errorf("Expected the first column as a key but got %v", tracker.candidates())
+	}
+	if len(tracker.live) != 1 {
+		t.Errorf("Expected 1 set to be tracked but got %d", len(tracker.live))
+	}
+}
+
+func TestHasEncodingProblem(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"Іван", false},
+		{"ТОВ «АТС»", false},
+		{"Р – 1", false},
+		{"С№5", false},
+		{"Р†РІР°РЅ", true},
+		{"РўРћР’ В«РђРўРЎВ»", true},
+		{"bad�", true},
+		{"line\x01", true},
+	}
+	for _, tt := range tests {
+		if result := hasEncodingProblem(tt.value); result != tt.expected {
+			t.Errorf("Expected %v for %q but got %v", tt.expected, tt.value, result)
+		}
+	}
+}
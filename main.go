@@ -7,41 +7,60 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/config"
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/convertor"
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/db"
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/inference"
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/profile"
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/sqlldr"
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/util"
 )
 
 func main() {
 	planCmd := flag.NewFlagSet("plan", flag.ExitOnError)
+	workers := planCmd.Int("workers", 1, "Number of goroutines used to infer column types (0 uses every CPU core)")
+	sample := planCmd.String("sample", "", "Infer column types from a sample: head, reservoir or stride")
+	sampleSize := planCmd.Int("sample-size", 0, "Rows in the head or reservoir sample, or k for every k-th row with stride")
+	keyPairs := planCmd.Bool("key-pairs", false, "Also look for pairs of columns that are unique together")
+	profileCmd := flag.NewFlagSet("profile", flag.ExitOnError)
+	profileFormat := profileCmd.String("format", profile.FormatTable, "Report format: table, json or html")
+	profileOutput := profileCmd.String("output", "", "Write the report to a file instead of stdout")
+	profileWorkers := profileCmd.Int("workers", 1, "Number of goroutines used to profile the file (0 uses every CPU core)")
+	profileSample := profileCmd.String("sample", "", "Profile a sample: head, reservoir or stride")
+	profileSampleSize := profileCmd.Int("sample-size", 0, "Rows in the head or reservoir sample, or k for every k-th row with stride")
 	applyCmd := flag.NewFlagSet("apply", flag.ExitOnError)
 	autoApprove := applyCmd.Bool("auto-approve", false, "Automatically approve the plan without prompt")
 	skipTable := applyCmd.Bool("skip-table", false, "Skip table creation")
+	loadMode := applyCmd.String("load-mode", "", "Override the load mode of the config: INSERT, APPEND, TRUNCATE, REPLACE or RECREATE")
 
 	flag.Parse()
 
 	if len(os.Args) < 2 {
-		log.Println("expected 'plan' or 'apply' subcommands")
+		log.Println("expected 'plan', 'profile' or 'apply' subcommands")
 		os.Exit(1)
 	}
 
 	switch os.Args[1] {
 	case "plan":
 		planCmd.Parse(os.Args[2:])
-		handlePlan()
+		handlePlan(generateOptions(*workers, *sample, *sampleSize, *keyPairs))
+	case "profile":
+		profileCmd.Parse(os.Args[2:])
+		opts := generateOptions(*profileWorkers, *profileSample, *profileSampleSize, false)
+		handleProfile(opts.Options, *profileFormat, *profileOutput)
 	case "apply":
 		applyCmd.Parse(os.Args[2:])
-		handleApply(*autoApprove, *skipTable)
+		handleApply(*autoApprove, *skipTable, *loadMode)
 	default:
-		log.Println("expected 'plan' or 'apply' subcommands")
+		log.Println("expected 'plan', 'profile' or 'apply' subcommands")
 		os.Exit(1)
 	}
 }
 
-func handlePlan() {
+func handlePlan(opts db.GenerateOptions) {
 	cfg := loadConfig()
 	delimiter := detectDelimiter(cfg.FilePath)
 	utf8FilePath := util.GenerateUtf8FilePath(cfg.FilePath)
@@ -68,7 +87,7 @@ func handlePlan() {
 		}
 	} else {
 		// Step 3: Generate the table configuration using the UTF-8 file
-		tableConfig = generateTableConfig(utf8FilePath, cfg, delimiter)
+		tableConfig = generateTableConfig(utf8FilePath, cfg, delimiter, opts)
 		saveTableConfigToFile(cfg, tableConfig)
 	}
 
@@ -82,8 +101,7 @@ func handlePlan() {
 	generateCtlFile(cfg, tableConfig, delimiter)
 
 	// Step 6: Generate and display the SQL statement for the table
-	sqlStatement := db.GenerateCreateTableSQL(tableConfig)
-	fmt.Printf("Planned table:\n%s\n", sqlStatement)
+	printPlan(tableConfig)
 
 	// Step 7: Clean up by removing the temporary UTF-8 file
 	err = os.Remove(utf8FilePath)
@@ -93,8 +111,96 @@ func handlePlan() {
 	log.Printf("Temporary UTF-8 file removed: %s\n", utf8FilePath)
 }
 
+func printPlan(tableConfig *db.TableConfig) {
+	if sampling := tableConfig.Metadata.Sampling; sampling != nil {
+		fmt.Printf("WARNING: column types and lengths are approximate, inferred from a %s sample of %d rows (%d rows read).\n",
+			sampling.Strategy, sampling.RowsSampled, tableConfig.Metadata.RowCount)
+	}
+
+	for _, colName := range tableConfig.ColumnsOrder {
+		if colInfo := tableConfig.Columns[colName]; colInfo.DuplicateOf != "" {
+			fmt.Printf("WARNING: header %q maps to the same name as column %s, created as %s.\n",
+				colInfo.OriginalName, colInfo.DuplicateOf, tableConfig.TargetName(colName))
+		}
+	}
+
+	var renamed []string
+	for _, colName := range tableConfig.ColumnsOrder {
+		if colInfo := tableConfig.Columns[colName]; colInfo.RenamedFrom != "" {
+			renamed = append(renamed, fmt.Sprintf("%s -> %s (%s)", colInfo.RenamedFrom, colName, colInfo.RenameReason))
+		}
+	}
+	if len(renamed) > 0 {
+		fmt.Printf("Renamed columns: %s\n", strings.Join(renamed, ", "))
+	}
+
+	var approximate []string
+	for _, colName := range tableConfig.ColumnsOrder {
+		if colInfo := tableConfig.Columns[colName]; colInfo.Create && colInfo.Approximate {
+			approximate = append(approximate, colName)
+		}
+	}
+	if len(approximate) > 0 {
+		fmt.Printf("Columns to review (set \"approximate\": false once checked): %s\n", strings.Join(approximate, ", "))
+	}
+
+	if len(tableConfig.KeyCandidates) > 0 && len(tableConfig.Keys) == 0 {
+		var candidates []string
+		for _, columns := range tableConfig.KeyCandidates {
+			candidates = append(candidates, "("+strings.Join(columns, ", ")+")")
+		}
+		fmt.Printf("Key candidates (add one under \"keys\" to create it): %s\n", strings.Join(candidates, ", "))
+	}
+
+	sqlStatement := db.GenerateCreateTableSQL(tableConfig)
+	fmt.Printf("Planned table:\n%s\n", sqlStatement)
+	loadMode, _ := db.ParseLoadMode(tableConfig.Metadata.LoadMode)
+	fmt.Printf("Load mode: %s\n", loadMode)
+}
+
+// handleProfile reports on the data file without writing the table config or
+// the .ctl file.
+func handleProfile(opts inference.Options, format, output string) {
+	switch format {
+	case profile.FormatTable, profile.FormatJSON, profile.FormatHTML:
+	default:
+		log.Fatalf("invalid report format %q, expected table, json or html", format)
+	}
 
-func handleApply(autoApprove, skipTable bool) {
+	cfg := loadConfig()
+	delimiter := detectDelimiter(cfg.FilePath)
+
+	file, err := os.Open(cfg.FilePath)
+	if err != nil {
+		log.Fatalf("error opening file: %v", err)
+	}
+	defer file.Close()
+
+	opts.Profile = true
+	table, err := inference.Scan(convertor.NewUtf8Reader(file), delimiter, opts)
+	if err != nil {
+		log.Fatalf("error profiling file: %v", err)
+	}
+	report := profile.NewReport(cfg.FilePath, table)
+
+	w := os.Stdout
+	if output != "" {
+		w, err = os.Create(output)
+		if err != nil {
+			log.Fatalf("error creating report file: %v", err)
+		}
+		defer w.Close()
+	}
+	err = profile.Write(w, report, format)
+	if err != nil {
+		log.Fatalf("error writing report: %v", err)
+	}
+	if output != "" {
+		log.Printf("Profile report saved to %s\n", output)
+	}
+}
+
+func handleApply(autoApprove, skipTable bool, loadMode string) {
 	cfg := loadConfig()
 	tableConfigFilePath := getTableConfigFilePath(cfg)
 
@@ -104,20 +210,65 @@ func handleApply(autoApprove, skipTable bool) {
 
 	tableConfig := loadTableConfigFromFile(tableConfigFilePath)
 
-	if !skipTable {
-		sqlStatement := db.GenerateCreateTableSQL(tableConfig)
-		fmt.Printf("The following table will be created:\n%s\n", sqlStatement)
+	if loadMode != "" {
+		mode, err := db.ParseLoadMode(loadMode)
+		if err != nil {
+			log.Fatalf("error in --load-mode: %v", err)
+		}
+		tableConfig.Metadata.LoadMode = mode
+	}
+	mode, _ := db.ParseLoadMode(tableConfig.Metadata.LoadMode)
+	if mode == db.LoadRecreate && skipTable {
+		log.Fatalf("The RECREATE load mode cannot be used with --skip-table.")
+	}
+	// The .ctl file loads with the method confirmed here
+	setLoadMethod(cfg, tableConfig)
+
+	rows := countRows(cfg, tableConfig)
+	// An existing table is only dropped together with its creation, after a
+	// single confirmation of both
+	recreate := mode == db.LoadRecreate && rows >= 0
+	if recreate {
+		fmt.Printf("Table %s will be dropped and created again:\n%s\n", tableConfig.Metadata.QualifiedTableName(), db.GenerateCreateTableSQL(tableConfig))
+	}
+	if !confirmLoadMode(tableConfig, mode, rows) {
+		fmt.Println("Operation cancelled.")
+		return
+	}
 
-		if !autoApprove && !confirmCreation() {
+	if recreate {
+		// A table with rows was confirmed by typing its name
+		if rows == 0 && !autoApprove && !confirm("Are you sure you want to recreate it?") {
 			fmt.Println("Operation cancelled.")
 			return
 		}
-
+		dropTable(cfg, tableConfig)
 		createTable(cfg, tableConfig)
+	} else if !skipTable {
+		changes, exists := planTableChanges(cfg, tableConfig)
+		if !exists {
+			sqlStatement := db.GenerateCreateTableSQL(tableConfig)
+			fmt.Printf("The following table will be created:\n%s\n", sqlStatement)
+
+			if !autoApprove && !confirm("Are you sure you want to create it?") {
+				fmt.Println("Operation cancelled.")
+				return
+			}
+
+			createTable(cfg, tableConfig)
+		} else if printChanges(tableConfig, changes) {
+			if !autoApprove && !confirm("Are you sure you want to alter it?") {
+				fmt.Println("Operation cancelled.")
+				return
+			}
+
+			alterTable(cfg, changes)
+		}
 	} else {
 		log.Println("Table creation skipped due to --skip-table flag.")
 	}
 
+	checkTable(cfg, tableConfig)
 	runSQLLoader(cfg)
 }
 
@@ -129,8 +280,40 @@ func loadConfig() *config.Config {
 	return cfg
 }
 
-func generateTableConfig(filePath string, cfg *config.Config, delimiter rune) *db.TableConfig {
-	tableConfig, err := db.GenerateTableConfig(filePath, cfg.TableName, delimiter)
+func generateOptions(workers int, sample string, sampleSize int, keyPairs bool) db.GenerateOptions {
+	var opts db.GenerateOptions
+	opts.Workers = workers
+	opts.KeyPairs = keyPairs
+	if workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+
+	opts.Sample, opts.SampleSize = sample, sampleSize
+	if sampleSize == 0 {
+		switch sample {
+		case inference.SampleHead, inference.SampleReservoir:
+			opts.SampleSize = 10000
+		case inference.SampleStride:
+			opts.SampleSize = 100
+		}
+	}
+	return opts
+}
+
+func generateTableConfig(filePath string, cfg *config.Config, delimiter rune, opts db.GenerateOptions) *db.TableConfig {
+	opts.LengthSemantics = strings.ToUpper(cfg.LengthSemantics)
+	opts.MaxStringSize = cfg.MaxStringSize
+	maxIdentifierLength, err := util.MaxIdentifierLength(cfg.OracleVersion)
+	if err != nil {
+		log.Fatalf("error reading config: %v", err)
+	}
+	opts.MaxIdentifierLength = maxIdentifierLength
+	opts.NamingStrategy = strings.ToLower(cfg.NamingStrategy)
+	opts.HeaderDictionary = cfg.HeaderDictionary
+	opts.HeaderRules = cfg.HeaderRules
+	opts.Owner = strings.ToUpper(cfg.TableOwner)
+	opts.Tablespace = strings.ToUpper(cfg.Tablespace)
+	tableConfig, err := db.GenerateTableConfig(filePath, cfg.TableName, delimiter, opts)
 	if err != nil {
 		log.Fatalf("error generating table config: %v", err)
 	}
@@ -157,8 +340,15 @@ func generateCtlFile(cfg *config.Config, tableConfig *db.TableConfig, delimiter
 	log.Printf("SQL*Loader control file generated: %s\n", ctlFilePath)
 }
 
-func confirmCreation() bool {
-	fmt.Print("Are you sure you want to create it? (yes/no): ")
+func setLoadMethod(cfg *config.Config, tableConfig *db.TableConfig) {
+	err := sqlldr.SetLoadMethod(getCtlFilePath(cfg), tableConfig.Metadata)
+	if err != nil {
+		log.Fatalf("error updating .ctl file: %v", err)
+	}
+}
+
+func confirm(question string) bool {
+	fmt.Printf("%s (yes/no): ", question)
 	var response string
 	fmt.Scanln(&response)
 	return response == "yes"
@@ -171,6 +361,97 @@ func createTable(cfg *config.Config, tableConfig *db.TableConfig) {
 	}
 }
 
+func countRows(cfg *config.Config, tableConfig *db.TableConfig) int {
+	rows, err := db.CountRows(cfg.DBUser, cfg.DBPassword, cfg.DBUrl, tableConfig.Metadata)
+	if err != nil {
+		log.Fatalf("error reading existing table: %v", err)
+	}
+	return rows
+}
+
+// confirmLoadMode asks before a load deletes existing rows, even with
+// --auto-approve. rows is -1 when the table does not exist yet.
+func confirmLoadMode(tableConfig *db.TableConfig, mode string, rows int) bool {
+	tableName := tableConfig.Metadata.QualifiedTableName()
+	if mode == db.LoadInsert && rows > 0 {
+		log.Fatalf("Table %s already has %d rows and the INSERT load mode only loads into an empty table. Use --load-mode APPEND to add the rows.", tableName, rows)
+	}
+	if !db.IsDestructiveLoadMode(mode) || rows <= 0 {
+		return true
+	}
+
+	fmt.Printf("WARNING: the %s load mode deletes all %d rows currently in table %s.\n", mode, rows, tableName)
+	fmt.Print("Type the table name to confirm: ")
+	var response string
+	fmt.Scanln(&response)
+	return strings.EqualFold(response, tableName)
+}
+
+func dropTable(cfg *config.Config, tableConfig *db.TableConfig) {
+	err := db.DropTable(cfg.DBUser, cfg.DBPassword, cfg.DBUrl, tableConfig.Metadata)
+	if err != nil {
+		log.Fatalf("error dropping table: %v", err)
+	}
+	log.Printf("Table %s dropped to be recreated", tableConfig.Metadata.QualifiedTableName())
+}
+
+func planTableChanges(cfg *config.Config, tableConfig *db.TableConfig) ([]db.Change, bool) {
+	changes, exists, err := db.PlanTableChanges(cfg.DBUser, cfg.DBPassword, cfg.DBUrl, tableConfig)
+	if err != nil {
+		log.Fatalf("error reading existing table: %v", err)
+	}
+	return changes, exists
+}
+
+// printChanges shows how the existing table differs from the config and
+// reports whether any statements are to be run.
+func printChanges(tableConfig *db.TableConfig, changes []db.Change) bool {
+	if len(changes) == 0 {
+		fmt.Printf("Table %s already exists and matches the configuration.\n", tableConfig.Metadata.QualifiedTableName())
+		return false
+	}
+
+	fmt.Printf("Table %s already exists and differs from the configuration:\n", tableConfig.Metadata.QualifiedTableName())
+	var statements []string
+	for _, change := range changes {
+		fmt.Println(change)
+		if change.SQL != "" {
+			statements = append(statements, change.SQL+";")
+		}
+	}
+	if len(statements) == 0 {
+		fmt.Println("No changes can be made automatically.")
+		return false
+	}
+	fmt.Printf("The following statements will be run:\n%s\n", strings.Join(statements, "\n"))
+	return true
+}
+
+func alterTable(cfg *config.Config, changes []db.Change) {
+	err := db.AlterTable(cfg.DBUser, cfg.DBPassword, cfg.DBUrl, changes)
+	if err != nil {
+		log.Fatalf("error altering table: %v", err)
+	}
+}
+
+// checkTable stops before loading when the table cannot take the data, so
+// rows are not rejected one by one into the bad file.
+func checkTable(cfg *config.Config, tableConfig *db.TableConfig) {
+	mismatches, err := db.CheckTableFromConfig(cfg.DBUser, cfg.DBPassword, cfg.DBUrl, tableConfig)
+	if err != nil {
+		log.Fatalf("error checking table before loading: %v", err)
+	}
+	if len(mismatches) == 0 {
+		return
+	}
+
+	fmt.Printf("Table %s does not match the configuration:\n", tableConfig.Metadata.QualifiedTableName())
+	for _, mismatch := range mismatches {
+		fmt.Println(mismatch)
+	}
+	log.Fatalf("Load cancelled, fix the table or the configuration first.")
+}
+
 func runSQLLoader(cfg *config.Config) {
 	output, err := sqlldr.RunSQLLoader(cfg.DBUser, cfg.DBPassword, cfg.DBUrl, getCtlFilePath(cfg))
 	if err != nil {
@@ -232,5 +513,9 @@ func loadTableConfigFromFile(filePath string) *db.TableConfig {
 	if err != nil {
 		log.Fatalf("error unmarshalling table config JSON: %v", err)
 	}
+	err = tableConfig.Validate()
+	if err != nil {
+		log.Fatalf("error in table config file %s: %v", filePath, err)
+	}
 	return &tableConfig
 }
@@ -0,0 +1,187 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/inference"
+)
+
+const (
+	// topValues is the number of frequent values shown for each column.
+	topValues = 5
+	// mixedShare is the share of values that must look like numbers or
+	// dates before the rest of a text column is reported as mixed.
+	mixedShare = 0.8
+)
+
+// Issue kinds reported for a column.
+const (
+	IssueMixedTypes = "mixed types"
+	IssueWhitespace = "whitespace"
+	IssueEncoding   = "encoding"
+)
+
+// Report is the data-quality report of a file.
+type Report struct {
+	File string `json:"file"`
+	Rows int    `json:"rows"`
+	// Sampled is the number of rows profiled when only a sample was read.
+	Sampled int      `json:"sampled,omitempty"`
+	Columns []Column `json:"columns"`
+}
+
+type Column struct {
+	Position  int     `json:"position"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	Kind      string  `json:"kind"`
+	FillRate  float64 `json:"fill_rate"`
+	Nulls     int     `json:"nulls"`
+	Distinct  uint64  `json:"distinct_estimate"`
+	Lengths   Lengths `json:"lengths"`
+	TopValues []Value `json:"top_values"`
+	Issues    []Issue `json:"issues,omitempty"`
+}
+
+// Lengths summarises the lengths of the values in characters.
+type Lengths struct {
+	Min       int      `json:"min"`
+	Median    int      `json:"median"`
+	P95       int      `json:"p95"`
+	Max       int      `json:"max"`
+	Histogram []Bucket `json:"histogram"`
+}
+
+// Bucket counts the values with a length between From and To.
+type Bucket struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Count int `json:"count"`
+}
+
+type Value struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type Issue struct {
+	Kind    string `json:"kind"`
+	Count   int    `json:"count"`
+	Example string `json:"example"`
+}
+
+// NewReport builds the report of a table scanned with Options.Profile set.
+func NewReport(file string, table *inference.Table) *Report {
+	report := &Report{File: file, Rows: table.Rows, Columns: make([]Column, len(table.Columns))}
+	if table.Sampled < table.Rows {
+		report.Sampled = table.Sampled
+	}
+	for i, col := range table.Columns {
+		result := col.Result()
+		column := Column{
+			Position: i + 1,
+			Name:     table.Headers[i],
+			Type:     typeName(result),
+			Kind:     result.Kind,
+			Nulls:    result.Nulls,
+			Distinct: result.Distinct,
+		}
+		if total := result.Values + result.Nulls; total > 0 {
+			column.FillRate = float64(result.Values) / float64(total)
+		}
+		if result.Profile != nil {
+			column.Lengths = lengths(result.Profile.Lengths)
+			for _, top := range result.Profile.TopValues[:min(topValues, len(result.Profile.TopValues))] {
+				column.TopValues = append(column.TopValues, Value{Value: top.Value, Count: top.Count})
+			}
+			column.Issues = issues(result)
+		}
+		report.Columns[i] = column
+	}
+	return report
+}
+
+func typeName(result inference.Result) string {
+	switch result.Type {
+	case "NUMBER":
+		if result.Scale > 0 {
+			return fmt.Sprintf("NUMBER(%d,%d)", result.Precision, result.Scale)
+		}
+		if result.Precision > 0 {
+			return fmt.Sprintf("NUMBER(%d)", result.Precision)
+		}
+		return result.Type
+	case "VARCHAR2", "CHAR":
+		return fmt.Sprintf("%s(%d)", result.Type, result.Length)
+	default:
+		return result.Type
+	}
+}
+
+// lengths finds the percentiles and builds a histogram with buckets that
+// double in width: 1, 2-3, 4-7 and so on.
+func lengths(counts map[int]int) Lengths {
+	var summary Lengths
+	if len(counts) == 0 {
+		return summary
+	}
+
+	keys := make([]int, 0, len(counts))
+	total := 0
+	for length, count := range counts {
+		keys = append(keys, length)
+		total += count
+	}
+	sort.Ints(keys)
+	summary.Min, summary.Max = keys[0], keys[len(keys)-1]
+
+	seen := 0
+	for _, length := range keys {
+		seen += counts[length]
+		if summary.Median == 0 && seen*2 >= total {
+			summary.Median = length
+		}
+		if seen*100 >= total*95 {
+			summary.P95 = length
+			break
+		}
+	}
+
+	for _, length := range keys {
+		from, to := 0, 0
+		if length > 0 {
+			from = 1
+			for from*2 <= length {
+				from *= 2
+			}
+			to = from*2 - 1
+		}
+		if n := len(summary.Histogram); n > 0 && summary.Histogram[n-1].From == from {
+			summary.Histogram[n-1].Count += counts[length]
+		} else {
+			summary.Histogram = append(summary.Histogram, Bucket{From: from, To: to, Count: counts[length]})
+		}
+	}
+	return summary
+}
+
+func issues(result inference.Result) []Issue {
+	var found []Issue
+	profile := result.Profile
+
+	// Codes are digits kept as text on purpose
+	if result.Kind == inference.KindText && profile.Other.Count > 0 {
+		typed := max(profile.Numeric, profile.Dated)
+		if float64(typed) >= mixedShare*float64(result.Values) {
+			found = append(found, Issue{Kind: IssueMixedTypes, Count: profile.Other.Count, Example: profile.Other.Example})
+		}
+	}
+	if profile.Whitespace.Count > 0 {
+		found = append(found, Issue{Kind: IssueWhitespace, Count: profile.Whitespace.Count, Example: profile.Whitespace.Example})
+	}
+	if profile.Encoding.Count > 0 {
+		found = append(found, Issue{Kind: IssueEncoding, Count: profile.Encoding.Count, Example: profile.Encoding.Example})
+	}
+	return found
+}
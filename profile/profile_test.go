@@ -0,0 +1,105 @@
+package profile
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/inference"
+)
+
+func TestNewReport(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("amount;status;name\n")
+	for i := 0; i < 20; i++ {
+		amount := fmt.Sprint(i * 10)
+		if i == 7 {
+			amount = "n/a"
+		}
+		status := "open"
+		if i%4 == 0 {
+			status = "closed"
+		}
+		name := "Іван"
+		switch i {
+		case 3:
+			name = " Іван"
+		case 5:
+			name = "Р†РІР°РЅ"
+		case 9:
+			name = ""
+		}
+		fmt.Fprintf(&sb, "%s;%s;%s\n", amount, status, name)
+	}
+
+	table, err := inference.Scan(strings.NewReader(sb.String()), ';', inference.Options{Profile: true})
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+	report := NewReport("test.csv", table)
+
+	if report.Rows != 20 || report.Sampled != 0 {
+		t.Errorf("Expected 20 rows without sampling but got %d/%d", report.Rows, report.Sampled)
+	}
+
+	amount := report.Columns[0]
+	expectedIssues := []Issue{{Kind: IssueMixedTypes, Count: 1, Example: "n/a"}}
+	if !reflect.DeepEqual(amount.Issues, expectedIssues) {
+		t.Errorf("Expected %+v for amount but got %+v", expectedIssues, amount.Issues)
+	}
+	expectedLengths := Lengths{Min: 1, Median: 3, P95: 3, Max: 3, Histogram: []Bucket{{1, 1, 1}, {2, 3, 19}}}
+	if !reflect.DeepEqual(amount.Lengths, expectedLengths) {
+		t.Errorf("Expected lengths %+v but got %+v", expectedLengths, amount.Lengths)
+	}
+
+	status := report.Columns[1]
+	expectedTop := []Value{{"open", 15}, {"closed", 5}}
+	if !reflect.DeepEqual(status.TopValues, expectedTop) || len(status.Issues) != 0 {
+		t.Errorf("Expected top values %+v and no issues but got %+v and %+v", expectedTop, status.TopValues, status.Issues)
+	}
+
+	name := report.Columns[2]
+	if name.FillRate != 0.95 || name.Nulls != 1 {
+		t.Errorf("Expected a fill rate of 0.95 with 1 null but got %v with %d", name.FillRate, name.Nulls)
+	}
+	expectedIssues = []Issue{
+		{Kind: IssueWhitespace, Count: 1, Example: " Іван"},
+		{Kind: IssueEncoding, Count: 1, Example: "Р†РІР°РЅ"},
+	}
+	if !reflect.DeepEqual(name.Issues, expectedIssues) {
+		t.Errorf("Expected %+v for name but got %+v", expectedIssues, name.Issues)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := &Report{File: "test.csv", Rows: 1, Columns: []Column{{
+		Position:  1,
+		Name:      "<name>",
+		Type:      "VARCHAR2(4)",
+		TopValues: []Value{{"Іван", 1}},
+		Issues:    []Issue{{Kind: IssueWhitespace, Count: 1, Example: " Іван"}},
+	}}}
+
+	tests := []struct {
+		format   string
+		contains string
+	}{
+		{FormatTable, `name>: whitespace in 1 values, e.g. " Іван"`},
+		{FormatJSON, `"top_values": [`},
+		{FormatHTML, `<td>&lt;name&gt;</td>`},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := Write(&sb, report, tt.format); err != nil {
+			t.Fatalf("Failed to write %s: %v", tt.format, err)
+		}
+		if !strings.Contains(sb.String(), tt.contains) {
+			t.Errorf("Expected %s report to contain %q but got:\n%s", tt.format, tt.contains, sb.String())
+		}
+	}
+
+	if err := Write(&strings.Builder{}, report, "xml"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,150 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report formats.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatHTML  = "html"
+)
+
+// exampleLength caps the values quoted in the terminal table.
+const exampleLength = 30
+
+// Write writes the report in one of the report formats.
+func Write(w io.Writer, report *Report, format string) error {
+	switch format {
+	case FormatTable:
+		return WriteTable(w, report)
+	case FormatJSON:
+		return WriteJSON(w, report)
+	case FormatHTML:
+		return WriteHTML(w, report)
+	default:
+		return fmt.Errorf("invalid report format %q, expected %s, %s or %s", format, FormatTable, FormatJSON, FormatHTML)
+	}
+}
+
+func WriteTable(w io.Writer, report *Report) error {
+	fmt.Fprintf(w, "File: %s\n%s\n\n", report.File, rowsSummary(report))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tCOLUMN\tTYPE\tFILL\tDISTINCT\tLENGTH MIN/MED/P95/MAX\tTOP VALUES")
+	for _, col := range report.Columns {
+		var top []string
+		for _, value := range col.TopValues {
+			top = append(top, fmt.Sprintf("%s (%d)", quote(value.Value), value.Count))
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%.1f%%\t~%d\t%d/%d/%d/%d\t%s\n",
+			col.Position, col.Name, col.Type, col.FillRate*100, col.Distinct,
+			col.Lengths.Min, col.Lengths.Median, col.Lengths.P95, col.Lengths.Max, strings.Join(top, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error writing report: %v", err)
+	}
+
+	var found bool
+	for _, col := range report.Columns {
+		for _, issue := range col.Issues {
+			if !found {
+				fmt.Fprintln(w, "\nIssues:")
+				found = true
+			}
+			fmt.Fprintf(w, "  %s: %s in %d values, e.g. %s\n", col.Name, issue.Kind, issue.Count, quote(issue.Example))
+		}
+	}
+	if !found {
+		fmt.Fprintln(w, "\nNo issues found.")
+	}
+	return nil
+}
+
+func WriteJSON(w io.Writer, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling report to JSON: %v", err)
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		return fmt.Errorf("error writing report: %v", err)
+	}
+	return nil
+}
+
+// WriteHTML writes a standalone page that needs no other files.
+func WriteHTML(w io.Writer, report *Report) error {
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("error writing report: %v", err)
+	}
+	return nil
+}
+
+func rowsSummary(report *Report) string {
+	if report.Sampled > 0 {
+		return fmt.Sprintf("Rows: %d (profiled a sample of %d)", report.Rows, report.Sampled)
+	}
+	return fmt.Sprintf("Rows: %d", report.Rows)
+}
+
+// quote shows leading and trailing spaces and shortens long values.
+func quote(value string) string {
+	if runes := []rune(value); len(runes) > exampleLength {
+		value = string(runes[:exampleLength]) + "…"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(share float64) string { return fmt.Sprintf("%.1f%%", share*100) },
+	"quote":   quote,
+	"rows":    rowsSummary,
+	"width": func(count int, buckets []Bucket) int {
+		largest := 0
+		for _, bucket := range buckets {
+			largest = max(largest, bucket.Count)
+		}
+		return count * 100 / max(largest, 1)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Profile of {{.File}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+.bar { background: #6a9fd8; height: 10px; }
+.issue { color: #b00020; }
+</style>
+</head>
+<body>
+<h1>Profile of {{.File}}</h1>
+<p>{{rows .}}</p>
+<table>
+<tr><th>#</th><th>Column</th><th>Type</th><th>Fill rate</th><th>Distinct</th><th>Lengths</th><th>Top values</th><th>Issues</th></tr>
+{{range .Columns}}<tr>
+<td>{{.Position}}</td>
+<td>{{.Name}}</td>
+<td>{{.Type}}<br><small>{{.Kind}}</small></td>
+<td>{{percent .FillRate}}<br><small>{{.Nulls}} empty</small></td>
+<td>~{{.Distinct}}</td>
+<td>min {{.Lengths.Min}}, median {{.Lengths.Median}}, p95 {{.Lengths.P95}}, max {{.Lengths.Max}}
+<table>{{$buckets := .Lengths.Histogram}}{{range $buckets}}
+<tr><td>{{.From}}–{{.To}}</td><td>{{.Count}}</td><td style="width: 120px"><div class="bar" style="width: {{width .Count $buckets}}%"></div></td></tr>{{end}}
+</table></td>
+<td>{{range .TopValues}}{{quote .Value}} ({{.Count}})<br>{{end}}</td>
+<td>{{range .Issues}}<div class="issue">{{.Kind}} in {{.Count}} values, e.g. {{quote .Example}}</div>{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/db"
@@ -13,8 +14,8 @@ import (
 func GenerateCtlFile(csvFilePath, ctlFilePath, tableName string, tableConfig *db.TableConfig, delimiter rune, infile string) error {
 	var fields []string
 	for _, colName := range tableConfig.ColumnsOrder {
-		if tableConfig.Columns[colName].Create {
-			fields = append(fields, colName)
+		if colInfo := tableConfig.Columns[colName]; colInfo.Create {
+			fields = append(fields, fieldSpec(tableConfig.ColumnSQL(colName), colInfo))
 		}
 	}
 	fieldsStr := strings.Join(fields, ",\n  ")
@@ -35,14 +36,14 @@ LOAD DATA
 CHARACTERSET CL8MSWIN1251
 %s
 INTO TABLE %s
-REPLACE
+%s
 %s
 
 TRAILING NULLCOLS
 (
   %s
 )
-`, badFileName, logFileName, infile, tableName, delimiterStr, fieldsStr)
+`, badFileName, logFileName, infile, tableConfig.Metadata.QualifiedTableName(), tableConfig.Metadata.LoadMethod(), delimiterStr, fieldsStr)
 
 	err := os.WriteFile(ctlFilePath, []byte(ctlContent), 0644)
 	if err != nil {
@@ -52,6 +53,105 @@ TRAILING NULLCOLS
 	return nil
 }
 
+var loadMethodLine = regexp.MustCompile(`(?m)^(INTO TABLE .+\n)(?:INSERT|APPEND|TRUNCATE|REPLACE)$`)
+
+// SetLoadMethod rewrites the load method of a .ctl file to the one of the
+// table metadata, leaving the rest of the file as it was generated.
+func SetLoadMethod(ctlFilePath string, metadata db.Metadata) error {
+	content, err := os.ReadFile(ctlFilePath)
+	if err != nil {
+		return fmt.Errorf("error reading .ctl file: %v", err)
+	}
+	if !loadMethodLine.Match(content) {
+		return fmt.Errorf("no load method found in %s", ctlFilePath)
+	}
+	content = loadMethodLine.ReplaceAll(content, []byte("${1}"+metadata.LoadMethod()))
+
+	err = os.WriteFile(ctlFilePath, content, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing .ctl file: %v", err)
+	}
+	return nil
+}
+
+// fieldSpec returns the field definition of a column in the control file.
+// Every field gets an explicit datatype: character fields would otherwise
+// default to 255 bytes and reject longer values.
+func fieldSpec(colName string, colInfo db.ColumnInfo) string {
+	length := max(colInfo.Length, 1)
+
+	columnType, _ := db.ParseType(colInfo.Type)
+	switch columnType.Name {
+	case "NUMBER", "NUMERIC", "INTEGER":
+		// A precision written in the type wins over the inferred one
+		if columnType.Precision > 0 {
+			colInfo.Precision, colInfo.Scale = columnType.Precision, columnType.Scale
+		}
+		switch {
+		case colInfo.NumericLocale != nil:
+			return fmt.Sprintf("%s CHAR(%d) \"%s\"", colName, length, numberExpression(colName, colInfo))
+		case columnType.Name == "INTEGER" || colInfo.Precision > 0 && colInfo.Scale == 0:
+			return fmt.Sprintf("%s INTEGER EXTERNAL(%d)", colName, length)
+		default:
+			return fmt.Sprintf("%s DECIMAL EXTERNAL(%d)", colName, length)
+		}
+	case "FLOAT", "BINARY_FLOAT", "BINARY_DOUBLE":
+		if colInfo.NumericLocale != nil {
+			return fmt.Sprintf("%s CHAR(%d) \"%s\"", colName, length, numberExpression(colName, colInfo))
+		}
+		return fmt.Sprintf("%s FLOAT EXTERNAL(%d)", colName, length)
+	case "DATE", "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITH LOCAL TIME ZONE":
+		if colInfo.Format == "" {
+			return fmt.Sprintf("%s %s", colName, columnType.Name)
+		}
+		return fmt.Sprintf("%s %s \"%s\"", colName, columnType.Name, colInfo.Format)
+	default:
+		// Text and LOB columns are read as character fields, and RAW columns
+		// as hexadecimal text
+		return fmt.Sprintf("%s CHAR(%d)", colName, length)
+	}
+}
+
+// numberExpression converts a localised number while loading: thousands
+// separators are removed and the decimal separator is passed to TO_NUMBER
+// through NLS_NUMERIC_CHARACTERS, so the result does not depend on the
+// session settings.
+func numberExpression(colName string, colInfo db.ColumnInfo) string {
+	// Double quotes of a quoted field name are escaped inside the SQL string
+	expr := ":" + strings.ReplaceAll(colName, `"`, `\"`)
+	for _, group := range colInfo.NumericLocale.GroupSeparators {
+		expr = fmt.Sprintf("REPLACE(%s, %s)", expr, sqlChar(group))
+	}
+
+	intDigits := colInfo.Precision - colInfo.Scale
+	if colInfo.Precision == 0 {
+		intDigits = 38
+	}
+	mask := strings.Repeat("9", max(intDigits, 1))
+	if colInfo.Scale > 0 {
+		mask += "D" + strings.Repeat("9", colInfo.Scale)
+	}
+
+	// The group character only has to differ from the decimal one, the
+	// separators themselves are already gone.
+	decimal, group := colInfo.NumericLocale.DecimalSeparator, ","
+	if decimal == "," {
+		group = "."
+	}
+
+	return fmt.Sprintf("TO_NUMBER(%s, '%s', 'NLS_NUMERIC_CHARACTERS=''%s%s''')", expr, mask, decimal, group)
+}
+
+// sqlChar returns a SQL literal for a single character.
+func sqlChar(r rune) string {
+	if r == '\u00a0' {
+		// A non-breaking space in the AL16UTF16 national character set, which
+		// does not depend on the database character set.
+		return "NCHR(160)"
+	}
+	return fmt.Sprintf("'%c'", r)
+}
+
 func RunSQLLoader(user, password, dsn, ctlFilePath string) (string, error) {
 	// Extract the table name from the control file path to use for the log and bad files
 	tableName := strings.TrimSuffix(filepath.Base(ctlFilePath), ".ctl")
@@ -0,0 +1,129 @@
+package sqlldr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/serhii-kaliuzhnyi-dev/oracle-file-uploader/db"
+)
+
+func TestFieldSpec(t *testing.T) {
+	tests := []struct {
+		colInfo  db.ColumnInfo
+		expected string
+	}{
+		{db.ColumnInfo{Type: "VARCHAR2", Length: 300}, `col CHAR(300)`},
+		{db.ColumnInfo{Type: "CLOB", Length: 5000}, `col CHAR(5000)`},
+		{db.ColumnInfo{Type: "NUMBER", Length: 5, Precision: 5}, `col INTEGER EXTERNAL(5)`},
+		{db.ColumnInfo{Type: "NUMBER", Length: 6, Precision: 5, Scale: 2}, `col DECIMAL EXTERNAL(6)`},
+		{db.ColumnInfo{Type: "NUMBER", Length: 4}, `col DECIMAL EXTERNAL(4)`},
+		{db.ColumnInfo{Type: "DATE", Length: 10, Format: "DD.MM.YYYY"}, `col DATE "DD.MM.YYYY"`},
+		{db.ColumnInfo{Type: "TIMESTAMP", Length: 19}, `col TIMESTAMP`},
+		{db.ColumnInfo{Type: "TIMESTAMP(3) WITH TIME ZONE", Length: 29, Format: "YYYY-MM-DD HH24:MI:SS.FF TZH:TZM"}, `col TIMESTAMP WITH TIME ZONE "YYYY-MM-DD HH24:MI:SS.FF TZH:TZM"`},
+		{db.ColumnInfo{Type: "NUMBER(10)", Length: 6, Precision: 5, Scale: 2}, `col INTEGER EXTERNAL(6)`},
+		{db.ColumnInfo{Type: "INTEGER", Length: 5}, `col INTEGER EXTERNAL(5)`},
+		{db.ColumnInfo{Type: "FLOAT", Length: 12}, `col FLOAT EXTERNAL(12)`},
+		{db.ColumnInfo{Type: "NVARCHAR2(50)", Length: 40}, `col CHAR(40)`},
+		{db.ColumnInfo{Type: "RAW", Length: 32}, `col CHAR(32)`},
+		{
+			db.ColumnInfo{Type: "NUMBER", Length: 12, Precision: 9, Scale: 2, NumericLocale: &db.NumericLocale{DecimalSeparator: ",", GroupSeparators: " \u00a0"}},
+			`col CHAR(12) "TO_NUMBER(REPLACE(REPLACE(:col, ' '), NCHR(160)), '9999999D99', 'NLS_NUMERIC_CHARACTERS='',.''')"`,
+		},
+	}
+
+	for _, tt := range tests {
+		result := fieldSpec("col", tt.colInfo)
+		if result != tt.expected {
+			t.Errorf("Expected %s but got %s", tt.expected, result)
+		}
+	}
+
+	colInfo := db.ColumnInfo{Type: "NUMBER", Length: 5, Precision: 5, NumericLocale: &db.NumericLocale{DecimalSeparator: ",", GroupSeparators: " "}}
+	expected := `"Сума" CHAR(5) "TO_NUMBER(REPLACE(:\"Сума\", ' '), '99999', 'NLS_NUMERIC_CHARACTERS='',.''')"`
+	if result := fieldSpec(`"Сума"`, colInfo); result != expected {
+		t.Errorf("Expected %s but got %s", expected, result)
+	}
+}
+
+func TestGenerateCtlFile_LoadMode(t *testing.T) {
+	tests := map[string]string{
+		"":              "INTO TABLE payments\nINSERT\n",
+		db.LoadAppend:   "INTO TABLE payments\nAPPEND\n",
+		db.LoadTruncate: "INTO TABLE payments\nTRUNCATE\n",
+		db.LoadRecreate: "INTO TABLE payments\nINSERT\n",
+	}
+
+	for mode, expected := range tests {
+		tableConfig := &db.TableConfig{
+			Columns:      map[string]db.ColumnInfo{"id": {Type: "NUMBER", Length: 5, Precision: 5, Create: true}},
+			Metadata:     db.Metadata{TableName: "payments", LoadMode: mode},
+			ColumnsOrder: []string{"id"},
+		}
+		ctlFilePath := filepath.Join(t.TempDir(), "payments.ctl")
+		if err := GenerateCtlFile("payments.csv", ctlFilePath, "payments", tableConfig, ';', "INFILE 'payments.csv'"); err != nil {
+			t.Fatalf("Failed to generate .ctl file: %v", err)
+		}
+		content, err := os.ReadFile(ctlFilePath)
+		if err != nil {
+			t.Fatalf("Failed to read .ctl file: %v", err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %q with load mode %q but got:\n%s", expected, mode, content)
+		}
+	}
+}
+
+func TestGenerateCtlFile_Owner(t *testing.T) {
+	tableConfig := &db.TableConfig{
+		Columns:      map[string]db.ColumnInfo{"id": {Type: "NUMBER", Length: 5, Precision: 5, Create: true}},
+		Metadata:     db.Metadata{TableName: "payments", Owner: "SALES"},
+		ColumnsOrder: []string{"id"},
+	}
+	ctlFilePath := filepath.Join(t.TempDir(), "payments.ctl")
+	if err := GenerateCtlFile("payments.csv", ctlFilePath, "payments", tableConfig, ';', "INFILE 'payments.csv'"); err != nil {
+		t.Fatalf("Failed to generate .ctl file: %v", err)
+	}
+	content, err := os.ReadFile(ctlFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read .ctl file: %v", err)
+	}
+	if expected := "INTO TABLE SALES.payments\n"; !strings.Contains(string(content), expected) {
+		t.Errorf("Expected %q but got:\n%s", expected, content)
+	}
+}
+
+func TestSetLoadMethod(t *testing.T) {
+	tableConfig := &db.TableConfig{
+		Columns:      map[string]db.ColumnInfo{"id": {Type: "NUMBER", Length: 5, Precision: 5, Create: true}},
+		Metadata:     db.Metadata{TableName: "payments"},
+		ColumnsOrder: []string{"id"},
+	}
+	ctlFilePath := filepath.Join(t.TempDir(), "payments.ctl")
+	if err := GenerateCtlFile("payments.csv", ctlFilePath, "payments", tableConfig, '|', "INFILE 'payments.csv'"); err != nil {
+		t.Fatalf("Failed to generate .ctl file: %v", err)
+	}
+
+	tests := map[string]string{
+		db.LoadTruncate: "INTO TABLE payments\nTRUNCATE\nFIELDS TERMINATED BY '|'",
+		db.LoadRecreate: "INTO TABLE payments\nINSERT\nFIELDS TERMINATED BY '|'",
+		db.LoadAppend:   "INTO TABLE payments\nAPPEND\nFIELDS TERMINATED BY '|'",
+	}
+	for _, mode := range []string{db.LoadTruncate, db.LoadRecreate, db.LoadAppend} {
+		if err := SetLoadMethod(ctlFilePath, db.Metadata{LoadMode: mode}); err != nil {
+			t.Fatalf("Failed to set load method %s: %v", mode, err)
+		}
+		content, err := os.ReadFile(ctlFilePath)
+		if err != nil {
+			t.Fatalf("Failed to read .ctl file: %v", err)
+		}
+		if !strings.Contains(string(content), tests[mode]) {
+			t.Errorf("Expected %q for %s but got:\n%s", tests[mode], mode, content)
+		}
+	}
+
+	if err := SetLoadMethod(filepath.Join(t.TempDir(), "missing.ctl"), db.Metadata{}); err == nil {
+		t.Errorf("Expected an error for a missing .ctl file")
+	}
+}
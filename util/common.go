@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 )
 
@@ -23,7 +22,7 @@ func GenerateUtf8FilePath(filePath string) string {
 
 func DetectDelimiter(filePath string) (rune, error) {
 	file, err := os.Open(filePath)
-	
+
 	if err != nil {
 		return 0, err
 	}
@@ -51,8 +50,3 @@ func DetectDelimiter(filePath string) (rune, error) {
 
 	return 0, fmt.Errorf("unable to detect delimiter")
 }
-
-func IsNumeric(s string) bool {
-	_, err := strconv.Atoi(s)
-	return err == nil
-}
\ No newline at end of file
@@ -0,0 +1,175 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Identifier limits in bytes before and since Oracle 12.2.
+const (
+	ShortIdentifierLength = 30
+	LongIdentifierLength  = 128
+)
+
+// reservedWords are the Oracle SQL reserved words, which cannot be used as
+// unquoted identifiers.
+var reservedWords = map[string]bool{
+	"ACCESS": true, "ADD": true, "ALL": true, "ALTER": true, "AND": true, "ANY": true, "AS": true, "ASC": true,
+	"AUDIT": true, "BETWEEN": true, "BY": true, "CHAR": true, "CHECK": true, "CLUSTER": true, "COLUMN": true,
+	"COLUMN_VALUE": true, "COMMENT": true, "COMPRESS": true, "CONNECT": true, "CREATE": true, "CURRENT": true,
+	"DATE": true, "DECIMAL": true, "DEFAULT": true, "DELETE": true, "DESC": true, "DISTINCT": true, "DROP": true,
+	"ELSE": true, "EXCLUSIVE": true, "EXISTS": true, "FILE": true, "FLOAT": true, "FOR": true, "FROM": true,
+	"GRANT": true, "GROUP": true, "HAVING": true, "IDENTIFIED": true, "IMMEDIATE": true, "IN": true,
+	"INCREMENT": true, "INDEX": true, "INITIAL": true, "INSERT": true, "INTEGER": true, "INTERSECT": true,
+	"INTO": true, "IS": true, "LEVEL": true, "LIKE": true, "LOCK": true, "LONG": true, "MAXEXTENTS": true,
+	"MINUS": true, "MLSLABEL": true, "MODE": true, "MODIFY": true, "NESTED_TABLE_ID": true, "NOAUDIT": true,
+	"NOCOMPRESS": true, "NOT": true, "NOWAIT": true, "NULL": true, "NUMBER": true, "OF": true, "OFFLINE": true,
+	"ON": true, "ONLINE": true, "OPTION": true, "OR": true, "ORDER": true, "PCTFREE": true, "PRIOR": true,
+	"PUBLIC": true, "RAW": true, "RENAME": true, "RESOURCE": true, "REVOKE": true, "ROW": true, "ROWID": true,
+	"ROWNUM": true, "ROWS": true, "SELECT": true, "SESSION": true, "SET": true, "SHARE": true, "SIZE": true,
+	"SMALLINT": true, "START": true, "SUCCESSFUL": true, "SYNONYM": true, "SYSDATE": true, "TABLE": true,
+	"THEN": true, "TO": true, "TRIGGER": true, "UID": true, "UNION": true, "UNIQUE": true, "UPDATE": true,
+	"USER": true, "VALIDATE": true, "VALUES": true, "VARCHAR": true, "VARCHAR2": true, "VIEW": true,
+	"WHENEVER": true, "WHERE": true, "WITH": true,
+}
+
+// IsReservedWord reports whether an identifier is an Oracle reserved word.
+func IsReservedWord(name string) bool {
+	return reservedWords[strings.ToUpper(name)]
+}
+
+// IsUnquotedIdentifier reports whether a name can be used as an identifier
+// without quotes: a letter followed by letters, digits, _, $ or #, which is
+// not a reserved word.
+func IsUnquotedIdentifier(name string) bool {
+	if name == "" || !isASCIILetter(name[0]) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		if c := name[i]; !isASCIILetter(c) && (c < '0' || c > '9') && c != '_' && c != '$' && c != '#' {
+			return false
+		}
+	}
+	return !IsReservedWord(name)
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// MaxIdentifierLength returns the identifier limit of an Oracle version such
+// as "11.2" or "19c". An empty version gets the limit every version accepts.
+func MaxIdentifierLength(version string) (int, error) {
+	if version == "" {
+		return ShortIdentifierLength, nil
+	}
+	parts := strings.SplitN(strings.TrimRightFunc(version, unicode.IsLetter), ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid Oracle version %q", version)
+	}
+	minor := 0
+	if len(parts) > 1 {
+		if minor, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, fmt.Errorf("invalid Oracle version %q", version)
+		}
+	}
+	if major > 12 || major == 12 && minor >= 2 {
+		return LongIdentifierLength, nil
+	}
+	return ShortIdentifierLength, nil
+}
+
+// SafeIdentifier makes a lower snake case name usable as an unquoted Oracle
+// identifier of at most maxBytes bytes. It returns the name unchanged with an
+// empty reason when nothing had to be fixed.
+func SafeIdentifier(name string, maxBytes int) (string, string) {
+	var reasons []string
+	if name == "" {
+		name = "col"
+		reasons = append(reasons, "no letters or digits")
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "col_" + name
+		reasons = append(reasons, "starts with a digit")
+	}
+	if IsReservedWord(name) {
+		name += "_col"
+		reasons = append(reasons, "reserved word")
+	}
+	if len(name) > maxBytes {
+		reasons = append(reasons, fmt.Sprintf("longer than %d bytes", maxBytes))
+		name = truncate(name, maxBytes)
+	}
+	return name, strings.Join(reasons, ", ")
+}
+
+// SafeQuotedIdentifier makes a name usable as a quoted Oracle identifier of
+// at most maxBytes bytes, which may not contain double quotes.
+func SafeQuotedIdentifier(name string, maxBytes int) (string, string) {
+	var reasons []string
+	if strings.Contains(name, `"`) {
+		name = strings.ReplaceAll(name, `"`, "")
+		reasons = append(reasons, "contains double quotes")
+	}
+	if name == "" {
+		name = "col"
+		reasons = append(reasons, "no characters left")
+	}
+	if len(name) > maxBytes {
+		reasons = append(reasons, fmt.Sprintf("longer than %d bytes", maxBytes))
+		name = truncate(name, maxBytes)
+	}
+	return name, strings.Join(reasons, ", ")
+}
+
+// truncate cuts a name to at most maxBytes bytes without splitting a
+// character, dropping trailing underscores and spaces.
+func truncate(name string, maxBytes int) string {
+	if len(name) > maxBytes {
+		name = name[:maxBytes]
+		for !utf8.ValidString(name) {
+			name = name[:len(name)-1]
+		}
+	}
+	return strings.TrimRight(name, "_ ")
+}
+
+// UniqueIdentifiers renames repeated names with a numeric suffix, so that
+// suma, suma becomes suma, suma_2, keeping within maxBytes. It also returns
+// for each name the position of the earlier name it repeated, or -1.
+func UniqueIdentifiers(names []string, maxBytes int) ([]string, []int) {
+	unique := make([]string, len(names))
+	duplicateOf := make([]int, len(names))
+	taken := make(map[string]bool, len(names))
+	for _, name := range names {
+		taken[name] = true
+	}
+
+	first := make(map[string]int, len(names))
+	for i, name := range names {
+		j, seen := first[name]
+		if !seen {
+			first[name] = i
+			unique[i], duplicateOf[i] = name, -1
+			continue
+		}
+		for n := 2; ; n++ {
+			suffix := "_" + strconv.Itoa(n)
+			candidate := name
+			if len(candidate)+len(suffix) > maxBytes {
+				candidate = truncate(candidate, maxBytes-len(suffix))
+			}
+			candidate += suffix
+			if !taken[candidate] {
+				taken[candidate] = true
+				unique[i], duplicateOf[i] = candidate, j
+				break
+			}
+		}
+	}
+	return unique, duplicateOf
+}
@@ -0,0 +1,63 @@
+package util
+
+import "testing"
+
+func TestSafeIdentifier(t *testing.T) {
+	long := "suma_oplaty_za_komunalni_posluhy_za_poperednii_period"
+	tests := []struct {
+		name     string
+		maxBytes int
+		expected string
+		reason   string
+	}{
+		{"suma", ShortIdentifierLength, "suma", ""},
+		{"date", ShortIdentifierLength, "date_col", "reserved word"},
+		{"number", ShortIdentifierLength, "number_col", "reserved word"},
+		{"1_kvartal", ShortIdentifierLength, "col_1_kvartal", "starts with a digit"},
+		{"", ShortIdentifierLength, "col", "no letters or digits"},
+		{long, ShortIdentifierLength, "suma_oplaty_za_komunalni_poslu", "longer than 30 bytes"},
+		{long, LongIdentifierLength, long, ""},
+	}
+
+	for _, tt := range tests {
+		result, reason := SafeIdentifier(tt.name, tt.maxBytes)
+		if result != tt.expected || reason != tt.reason {
+			t.Errorf("Expected %s (%q) for %s but got %s (%q)", tt.expected, tt.reason, tt.name, result, reason)
+		}
+	}
+}
+
+func TestMaxIdentifierLength(t *testing.T) {
+	tests := map[string]int{
+		"":     ShortIdentifierLength,
+		"11.2": ShortIdentifierLength,
+		"12.1": ShortIdentifierLength,
+		"12.2": LongIdentifierLength,
+		"19c":  LongIdentifierLength,
+		"23ai": LongIdentifierLength,
+	}
+
+	for version, expected := range tests {
+		result, err := MaxIdentifierLength(version)
+		if err != nil || result != expected {
+			t.Errorf("Expected %d for %q but got %d (%v)", expected, version, result, err)
+		}
+	}
+	if _, err := MaxIdentifierLength("latest"); err == nil {
+		t.Errorf("Expected an error for an invalid version")
+	}
+}
+
+func TestUniqueIdentifiers(t *testing.T) {
+	names := []string{"suma", "suma", "suma_2", "data", "suma", "suma_oplaty_za_komunalni_poslu", "suma_oplaty_za_komunalni_poslu"}
+	expected := []string{"suma", "suma_3", "suma_2", "data", "suma_4", "suma_oplaty_za_komunalni_poslu", "suma_oplaty_za_komunalni_pos_2"}
+	expectedDuplicateOf := []int{-1, 0, -1, -1, 0, -1, 5}
+
+	result, duplicateOf := UniqueIdentifiers(names, ShortIdentifierLength)
+
+	for i := range names {
+		if result[i] != expected[i] || duplicateOf[i] != expectedDuplicateOf[i] {
+			t.Errorf("Expected %s (%d) but got %s (%d)", expected[i], expectedDuplicateOf[i], result[i], duplicateOf[i])
+		}
+	}
+}
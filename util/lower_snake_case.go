@@ -10,8 +10,8 @@ func ToLowerSnakeCase(s string) string {
 	s = strings.ReplaceAll(s, " ", "_")
 	// Convert to lower case
 	s = strings.ToLower(s)
-	// Use regex to replace any sequence of non-word characters with a single underscore
-	re := regexp.MustCompile(`[^\w]+`)
+	// Use regex to replace any sequence of non-word characters and underscores with a single underscore
+	re := regexp.MustCompile(`[^a-z0-9]+`)
 	s = re.ReplaceAllString(s, "_")
 	// Remove any leading or trailing underscores
 	s = strings.Trim(s, "_")
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mozillazg/go-unidecode"
+)
+
+// Naming strategies turn file headers into column names.
+const (
+	// NamingUnidecode transliterates with unidecode, the default.
+	NamingUnidecode = "unidecode"
+	// NamingKMU2010 uses the official Ukrainian transliteration adopted by
+	// the Cabinet of Ministers resolution No. 55 of 2010.
+	NamingKMU2010 = "kmu-2010"
+	// NamingGOST779 uses the Russian standard GOST 7.79-2000, system B.
+	NamingGOST779 = "gost-7.79"
+	// NamingPassthrough keeps the headers and creates quoted identifiers.
+	NamingPassthrough = "passthrough"
+)
+
+var kmu2010 = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e", 'є': "ie", 'ж': "zh",
+	'з': "z", 'и': "y", 'і': "i", 'ї': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+	'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ь': "", 'ю': "iu", 'я': "ia", '\'': "", '’': "", 'ʼ': "",
+}
+
+// kmu2010Initial holds the spellings used at the start of a word.
+var kmu2010Initial = map[rune]string{'є': "ye", 'ї': "yi", 'й': "y", 'ю': "yu", 'я': "ya"}
+
+var gost779 = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g`", 'д': "d", 'е': "e", 'ё': "yo", 'є': "ye",
+	'ж': "zh", 'з': "z", 'и': "i", 'і': "i`", 'ї': "yi", 'й': "j", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x",
+	'ц': "cz", 'ч': "ch", 'ш': "sh", 'щ': "shh", 'ъ': "``", 'ы': "y`", 'ь': "`", 'э': "e`",
+	'ю': "yu", 'я': "ya", '\'': "", '’': "", 'ʼ': "",
+}
+
+// IsNamingStrategy reports whether a naming strategy is known. The empty
+// strategy is the default.
+func IsNamingStrategy(strategy string) bool {
+	switch strategy {
+	case "", NamingUnidecode, NamingKMU2010, NamingGOST779, NamingPassthrough:
+		return true
+	default:
+		return false
+	}
+}
+
+// ColumnNames turns headers into column names with a naming strategy. Names
+// are in lower snake case, except with passthrough where the headers are
+// kept. Empty headers are named empty1, empty2 and so on.
+func ColumnNames(headers []string, strategy string) ([]string, error) {
+	if !IsNamingStrategy(strategy) {
+		return nil, fmt.Errorf("invalid naming strategy %q, expected %s, %s, %s or %s",
+			strategy, NamingUnidecode, NamingKMU2010, NamingGOST779, NamingPassthrough)
+	}
+
+	names := make([]string, len(headers))
+	emptyCount := 1
+	for i, header := range headers {
+		switch {
+		case header == "":
+			names[i] = "empty" + strconv.Itoa(emptyCount)
+			emptyCount++
+		case strategy == NamingPassthrough:
+			names[i] = strings.TrimSpace(header)
+		default:
+			names[i] = ToLowerSnakeCase(Transliterate(header, strategy))
+		}
+	}
+	return names, nil
+}
+
+// Transliterate writes Cyrillic text in Latin letters. Characters a
+// standard does not cover are transliterated with unidecode.
+func Transliterate(s, strategy string) string {
+	switch strategy {
+	case NamingKMU2010:
+		return transliterateKMU2010(s)
+	case NamingGOST779:
+		return transliterateGOST779(s)
+	case NamingPassthrough:
+		return s
+	default:
+		return unidecode.Unidecode(s)
+	}
+}
+
+func transliterateKMU2010(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		latin, ok := kmu2010[lower]
+		if !ok {
+			sb.WriteString(unidecode.Unidecode(string(r)))
+			continue
+		}
+		// An apostrophe does not start a new word: Знам'янка is Znamianka
+		if initial, ok := kmu2010Initial[lower]; ok && (i == 0 || !unicode.IsLetter(runes[i-1]) && !isApostrophe(runes[i-1])) {
+			latin = initial
+		}
+		// зг is written zgh to tell it from ж
+		if lower == 'г' && i > 0 && unicode.ToLower(runes[i-1]) == 'з' {
+			latin = "gh"
+		}
+		writeLatin(&sb, latin, unicode.IsUpper(r))
+	}
+	return sb.String()
+}
+
+func transliterateGOST779(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		lower := unicode.ToLower(r)
+		latin, ok := gost779[lower]
+		if !ok {
+			sb.WriteString(unidecode.Unidecode(string(r)))
+			continue
+		}
+		// ц is written c before i, e, y and j
+		if lower == 'ц' && i+1 < len(runes) {
+			if next := gost779[unicode.ToLower(runes[i+1])]; next != "" && strings.IndexByte("eijy", next[0]) >= 0 {
+				latin = "c"
+			}
+		}
+		writeLatin(&sb, latin, unicode.IsUpper(r))
+	}
+	return sb.String()
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’' || r == 'ʼ'
+}
+
+func writeLatin(sb *strings.Builder, latin string, upper bool) {
+	if upper && latin != "" {
+		first, size := utf8.DecodeRuneInString(latin)
+		sb.WriteRune(unicode.ToUpper(first))
+		latin = latin[size:]
+	}
+	sb.WriteString(latin)
+}
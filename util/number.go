@@ -0,0 +1,165 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxExponent keeps parsed values inside the range of Oracle NUMBER.
+const maxExponent = 125
+
+// Number describes the shape of a numeric value as Oracle would store it.
+type Number struct {
+	Negative bool
+	// IntDigits is the number of significant digits left of the decimal
+	// separator, FracDigits the number right of it.
+	IntDigits  int
+	FracDigits int
+	// Decimal is the decimal separator used by the value, or 0 if it had
+	// none.
+	Decimal rune
+	// Groups lists the thousands separators used by the value.
+	Groups string
+}
+
+// NumberLocale is a convention for writing numbers: the decimal separator
+// and the characters allowed between groups of thousands.
+type NumberLocale struct {
+	Decimal rune
+	Groups  string
+}
+
+// NumberLocales are the conventions recognised in source files, in order of
+// preference. Comma decimals come first as our files are mostly Ukrainian
+// exports that write amounts as 1 234 567,89, often with a non-breaking space.
+var NumberLocales = []NumberLocale{
+	{Decimal: ',', Groups: " \u00a0."},
+	{Decimal: '.', Groups: " \u00a0,"},
+}
+
+// ParseNumber parses a signed decimal number that uses '.' or ',' as the
+// decimal separator and may be written in scientific notation. Thousands
+// separators are not allowed.
+func ParseNumber(s string) (Number, bool) {
+	return parseNumber(s, ".,", "")
+}
+
+// Parse parses a signed decimal number written in the locale, with optional
+// thousands separators and scientific notation.
+func (l NumberLocale) Parse(s string) (Number, bool) {
+	return parseNumber(s, string(l.Decimal), l.Groups)
+}
+
+// Float returns the value of a number written in the locale.
+func (l NumberLocale) Float(s string) (float64, error) {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == l.Decimal:
+			return '.'
+		case strings.ContainsRune(l.Groups, r):
+			return -1
+		}
+		return r
+	}, s)
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseNumber(s, decimals, groups string) (Number, bool) {
+	var num Number
+	if s == "" {
+		return num, false
+	}
+
+	if s[0] == '+' || s[0] == '-' {
+		num.Negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	mantissa, exponent := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxExponent || exp < -maxExponent {
+			return num, false
+		}
+		mantissa, exponent = s[:i], exp
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexAny(mantissa, decimals); i >= 0 {
+		num.Decimal = rune(mantissa[i])
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+
+	if groups != "" && strings.ContainsAny(intPart, groups) {
+		var ok bool
+		intPart, num.Groups, ok = ungroup(intPart, groups)
+		if !ok {
+			return num, false
+		}
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return num, false
+	}
+
+	// Move the decimal point by the exponent and drop leading zeros to count
+	// significant digits the way NUMBER(p,s) does.
+	digits := intPart + fracPart
+	point := len(intPart) + exponent
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+		point--
+	}
+
+	switch {
+	case digits == "":
+		num.FracDigits = max(len(fracPart)-exponent, 0)
+	case point <= 0:
+		num.FracDigits = len(digits) - point
+	case point >= len(digits):
+		num.IntDigits = point
+	default:
+		num.IntDigits = point
+		num.FracDigits = len(digits) - point
+	}
+
+	return num, true
+}
+
+// ungroup removes thousands separators from the integer part of a number. The
+// first group may have one to three digits and no leading zero, every
+// following group exactly three, so 0.125 is not read as 125.
+func ungroup(s, groups string) (string, string, bool) {
+	if strings.HasPrefix(s, "0") {
+		return "", "", false
+	}
+	var digits strings.Builder
+	var used string
+	groupLen, first := 0, true
+	for _, r := range s {
+		if !strings.ContainsRune(groups, r) {
+			digits.WriteRune(r)
+			groupLen++
+			continue
+		}
+		if groupLen == 0 || groupLen > 3 || !first && groupLen != 3 {
+			return "", "", false
+		}
+		if !strings.ContainsRune(used, r) {
+			used += string(r)
+		}
+		groupLen, first = 0, false
+	}
+	if groupLen != 3 {
+		return "", "", false
+	}
+	return digits.String(), used, true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
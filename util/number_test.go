@@ -0,0 +1,76 @@
+package util
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value      string
+		intDigits  int
+		fracDigits int
+		negative   bool
+	}{
+		{"12", 2, 0, false},
+		{"-12.50", 2, 2, true},
+		{"+0.05", 0, 2, false},
+		{"1234,56", 4, 2, false},
+		{"1e3", 4, 0, false},
+		{"1.5E-3", 0, 4, false},
+		{".5", 0, 1, false},
+		{"0", 0, 0, false},
+		{"123456789012345", 15, 0, false},
+	}
+
+	for _, tt := range tests {
+		num, ok := ParseNumber(tt.value)
+		if !ok {
+			t.Errorf("Expected %s to be numeric", tt.value)
+			continue
+		}
+		if num.IntDigits != tt.intDigits || num.FracDigits != tt.fracDigits || num.Negative != tt.negative {
+			t.Errorf("Expected %d/%d/%v for %s but got %d/%d/%v", tt.intDigits, tt.fracDigits, tt.negative, tt.value, num.IntDigits, num.FracDigits, num.Negative)
+		}
+	}
+}
+
+func TestParseNumber_Invalid(t *testing.T) {
+	for _, value := range []string{"", "-", ".", "abc", "1.2.3", "1e", "1e999", "NaN", "Inf", "0x10", "12a"} {
+		if _, ok := ParseNumber(value); ok {
+			t.Errorf("Expected %q not to be numeric", value)
+		}
+	}
+}
+
+func TestNumberLocale_Parse(t *testing.T) {
+	comma, point := NumberLocales[0], NumberLocales[1]
+	tests := []struct {
+		locale     NumberLocale
+		value      string
+		intDigits  int
+		fracDigits int
+		groups     string
+	}{
+		{comma, "1 234 567,89", 7, 2, " "},
+		{comma, "1\u00a0234\u00a0567,89", 7, 2, "\u00a0"},
+		{comma, "1.234.567", 7, 0, "."},
+		{comma, "-12,5", 2, 1, ""},
+		{point, "1,234,567.89", 7, 2, ","},
+		{point, "1 234.5", 4, 1, " "},
+	}
+
+	for _, tt := range tests {
+		num, ok := tt.locale.Parse(tt.value)
+		if !ok {
+			t.Errorf("Expected %q to be numeric", tt.value)
+			continue
+		}
+		if num.IntDigits != tt.intDigits || num.FracDigits != tt.fracDigits || num.Groups != tt.groups {
+			t.Errorf("Expected %d/%d/%q for %q but got %d/%d/%q", tt.intDigits, tt.fracDigits, tt.groups, tt.value, num.IntDigits, num.FracDigits, num.Groups)
+		}
+	}
+
+	for _, value := range []string{"1 23,5", "12 3456", " 123", "123 ", "1,234.5"} {
+		if _, ok := comma.Parse(value); ok {
+			t.Errorf("Expected %q not to be numeric", value)
+		}
+	}
+}
@@ -11,9 +11,12 @@ import (
 
 func TestTransliterateHeaders_UTF8(t *testing.T) {
 	headers := []string{"Привет", "Мир", "Тест"}
-	expected := []string{"Privet", "Mir", "Test"}
+	expected := []string{"privet", "mir", "test"}
 
-	result := TransliterateHeaders(headers)
+	result, err := ColumnNames(headers, NamingUnidecode)
+	if err != nil {
+		t.Fatalf("Failed to name columns: %v", err)
+	}
 
 	for i, header := range result {
 		if header != expected[i] {
@@ -24,7 +27,7 @@ func TestTransliterateHeaders_UTF8(t *testing.T) {
 
 func TestTransliterateHeaders_Windows1251(t *testing.T) {
 	headers := []string{"Привет", "Мир", "Тест"}
-	expected := []string{"Privet", "Mir", "Test"}
+	expected := []string{"privet", "mir", "test"}
 
 	// Encode headers to Windows-1251
 	var windows1251Headers []string
@@ -46,7 +49,10 @@ func TestTransliterateHeaders_Windows1251(t *testing.T) {
 		utf8Headers = append(utf8Headers, decodedHeader)
 	}
 
-	result := TransliterateHeaders(utf8Headers)
+	result, err := ColumnNames(utf8Headers, NamingUnidecode)
+	if err != nil {
+		t.Fatalf("Failed to name columns: %v", err)
+	}
 
 	for i, header := range result {
 		if header != expected[i] {
@@ -59,7 +65,10 @@ func TestTransliterateHeaders_Empty(t *testing.T) {
 	headers := []string{"", ""}
 	expected := []string{"empty1", "empty2"}
 
-	result := TransliterateHeaders(headers)
+	result, err := ColumnNames(headers, NamingUnidecode)
+	if err != nil {
+		t.Fatalf("Failed to name columns: %v", err)
+	}
 
 	for i, header := range result {
 		if header != expected[i] {
@@ -70,9 +79,12 @@ func TestTransliterateHeaders_Empty(t *testing.T) {
 
 func TestTransliterateHeaders_Mixed(t *testing.T) {
 	headers := []string{"Привет", "", "Тест", ""}
-	expected := []string{"Privet", "empty1", "Test", "empty2"}
+	expected := []string{"privet", "empty1", "test", "empty2"}
 
-	result := TransliterateHeaders(headers)
+	result, err := ColumnNames(headers, NamingUnidecode)
+	if err != nil {
+		t.Fatalf("Failed to name columns: %v", err)
+	}
 
 	for i, header := range result {
 		if header != expected[i] {
@@ -102,3 +114,69 @@ func decodeWindows1251(s string) (string, error) {
 	}
 	return buf.String(), nil
 }
+
+func TestTransliterate_KMU2010(t *testing.T) {
+	tests := map[string]string{
+		"Щоденний":      "Shchodennyi",
+		"Згорани":       "Zghorany",
+		"Їжакевич":      "Yizhakevych",
+		"Київ":          "Kyiv",
+		"Єнакієве":      "Yenakiieve",
+		"Юрій":          "Yurii",
+		"Знам'янка":     "Znamianka",
+		"Подільськ":     "Podilsk",
+		"Дата операції": "Data operatsii",
+	}
+
+	for header, expected := range tests {
+		if result := Transliterate(header, NamingKMU2010); result != expected {
+			t.Errorf("Expected %s for %s but got %s", expected, header, result)
+		}
+	}
+}
+
+func TestTransliterate_GOST779(t *testing.T) {
+	tests := map[string]string{
+		"Щука":         "Shhuka",
+		"Цена":         "Cena",
+		"Улица":        "Ulicza",
+		"Объём":        "Ob``yom",
+		"Счёт-фактура": "Schyot-faktura",
+	}
+
+	for header, expected := range tests {
+		if result := Transliterate(header, NamingGOST779); result != expected {
+			t.Errorf("Expected %s for %s but got %s", expected, header, result)
+		}
+	}
+}
+
+func TestColumnNames(t *testing.T) {
+	headers := []string{"Щоденний обсяг", "", "Сума, грн"}
+	tests := []struct {
+		strategy string
+		expected []string
+	}{
+		{"", []string{"shchodennii_obsiag", "empty1", "suma_grn"}},
+		{NamingUnidecode, []string{"shchodennii_obsiag", "empty1", "suma_grn"}},
+		{NamingKMU2010, []string{"shchodennyi_obsiah", "empty1", "suma_hrn"}},
+		{NamingGOST779, []string{"shhodennij_obsyag", "empty1", "suma_grn"}},
+		{NamingPassthrough, []string{"Щоденний обсяг", "empty1", "Сума, грн"}},
+	}
+
+	for _, tt := range tests {
+		result, err := ColumnNames(headers, tt.strategy)
+		if err != nil {
+			t.Fatalf("Failed to name columns with %q: %v", tt.strategy, err)
+		}
+		for i := range headers {
+			if result[i] != tt.expected[i] {
+				t.Errorf("Expected %s with %q but got %s", tt.expected[i], tt.strategy, result[i])
+			}
+		}
+	}
+
+	if _, err := ColumnNames(headers, "iso-9"); err == nil {
+		t.Errorf("Expected an error for an unknown naming strategy")
+	}
+}